@@ -0,0 +1,99 @@
+package termenv
+
+// AdaptiveColor is a color that depends on whether the terminal uses a light
+// or a dark background.
+//
+// Output.Convert resolves adaptive colors against the Output's background
+// color, querying the terminal if necessary. Styles created with Output.String
+// resolve them when rendering, but never query the terminal: they use the
+// Output's background color if it was cached with WithColorCache. Otherwise,
+// the Dark color gets used.
+type AdaptiveColor struct {
+	Light Color
+	Dark  Color
+}
+
+// resolve returns the color to use on a dark or light background.
+func (c AdaptiveColor) resolve(dark bool) Color {
+	if dark {
+		return c.Dark
+	}
+	return c.Light
+}
+
+// Sequence returns the ANSI Sequence for the Dark color.
+func (c AdaptiveColor) Sequence(bg bool) string {
+	col := c.Dark
+	if col == nil {
+		return ""
+	}
+	return col.Sequence(bg)
+}
+
+// UnderlineSequence returns the ANSI Sequence for using the Dark color as
+// underline color.
+func (c AdaptiveColor) UnderlineSequence() string {
+	if u, ok := c.Dark.(underlineColor); ok {
+		return u.UnderlineSequence()
+	}
+	return ""
+}
+
+// CompleteColor is a color with explicit values for each color profile, which
+// get used instead of converting colors automatically. If the value for a
+// profile is nil, the value with the highest fidelity gets converted instead.
+//
+// Styles and Profile.Convert resolve complete colors against their profile.
+// Otherwise, the value with the highest fidelity gets used.
+type CompleteColor struct {
+	TrueColor Color
+	ANSI256   Color
+	ANSI      Color
+}
+
+// resolve returns the color to use for profile p. It reports whether the
+// color was set explicitly for p, or needs to be converted still.
+func (c CompleteColor) resolve(p Profile) (Color, bool) {
+	var col Color
+	switch p {
+	case TrueColor:
+		col = c.TrueColor
+	case ANSI256:
+		col = c.ANSI256
+	case ANSI:
+		col = c.ANSI
+	}
+	if col != nil {
+		return col, true
+	}
+
+	return c.best(), false
+}
+
+// best returns the value with the highest fidelity, or nil if no value is set.
+func (c CompleteColor) best() Color {
+	for _, col := range []Color{c.TrueColor, c.ANSI256, c.ANSI} {
+		if col != nil {
+			return col
+		}
+	}
+	return nil
+}
+
+// Sequence returns the ANSI Sequence for the value with the highest fidelity.
+func (c CompleteColor) Sequence(bg bool) string {
+	col := c.best()
+	if col == nil {
+		return ""
+	}
+	return col.Sequence(bg)
+}
+
+// UnderlineSequence returns the ANSI Sequence for using the value with the
+// highest fidelity as underline color.
+func (c CompleteColor) UnderlineSequence() string {
+	if u, ok := c.best().(underlineColor); ok {
+		return u.UnderlineSequence()
+	}
+	return ""
+}
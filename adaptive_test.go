@@ -0,0 +1,123 @@
+package termenv
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAdaptiveColorConvert(t *testing.T) {
+	c := AdaptiveColor{
+		Light: RGBColor("#ff0000"),
+		Dark:  ANSI256Color(196),
+	}
+
+	exp := AdaptiveColor{
+		Light: ANSIBrightRed,
+		Dark:  ANSIBrightRed,
+	}
+	if v := ANSI.Convert(c); v != exp {
+		t.Errorf("Expected %#v, got %#v", exp, v)
+	}
+
+	// outputs which are not a terminal are assumed to have a dark background
+	o := NewOutput(&bytes.Buffer{}, WithProfile(TrueColor))
+	if v := o.Convert(c); v != ANSI256Color(196) {
+		t.Errorf("Expected %#v, got %#v", ANSI256Color(196), v)
+	}
+}
+
+func TestCompleteColorConvert(t *testing.T) {
+	c := CompleteColor{
+		TrueColor: RGBColor("#ff8800"),
+		ANSI256:   ANSI256Color(208),
+		ANSI:      ANSIYellow,
+	}
+
+	tt := []struct {
+		Profile  Profile
+		Color    CompleteColor
+		Expected Color
+	}{
+		{TrueColor, c, RGBColor("#ff8800")},
+		{ANSI256, c, ANSI256Color(208)},
+		{ANSI, c, ANSIYellow},
+		{Ascii, c, NoColor{}},
+		{ANSI256, CompleteColor{TrueColor: RGBColor("#ff8800")}, ANSI256Color(208)},
+		{ANSI, CompleteColor{TrueColor: RGBColor("#ff8800")}, ANSIBrightRed},
+		{TrueColor, CompleteColor{ANSI: ANSIYellow}, ANSIYellow},
+		{TrueColor, CompleteColor{}, nil},
+	}
+
+	for _, test := range tt {
+		t.Run("", func(t *testing.T) {
+			if v := test.Profile.Convert(test.Color); v != test.Expected {
+				t.Errorf("Expected %#v, got %#v", test.Expected, v)
+			}
+		})
+	}
+}
+
+func TestCompleteColorSequence(t *testing.T) {
+	c := CompleteColor{
+		TrueColor: RGBColor("#ff8800"),
+		ANSI256:   ANSI256Color(208),
+	}
+
+	exp := "38;2;255;136;0"
+	if s := c.Sequence(false); s != exp {
+		t.Errorf("Expected %s, got %s", exp, s)
+	}
+
+	exp = "58;2;255;136;0"
+	if s := c.UnderlineSequence(); s != exp {
+		t.Errorf("Expected %s, got %s", exp, s)
+	}
+}
+
+func TestCompleteColorStyle(t *testing.T) {
+	// styles must not depend on the default output's profile
+	defer SetDefaultOutput(DefaultOutput())
+	SetDefaultOutput(NewOutput(&bytes.Buffer{}, WithProfile(Ascii)))
+
+	c := CompleteColor{
+		TrueColor: RGBColor("#ff0000"),
+		ANSI:      ANSIBlue,
+	}
+
+	tt := []struct {
+		Style    Style
+		Expected string
+	}{
+		{TrueColor.String("foobar").Foreground(c), "\x1b[38;2;255;0;0mfoobar\x1b[0m"},
+		{ANSI256.String("foobar").Foreground(c), "\x1b[38;5;196mfoobar\x1b[0m"},
+		{ANSI256.String("foobar").UnderlineColor(c), "\x1b[58;5;196mfoobar\x1b[0m"},
+		{ANSI.String("foobar").Foreground(c).Background(c), "\x1b[34;44mfoobar\x1b[0m"},
+		{Ascii.String("foobar").Foreground(c), "foobar"},
+	}
+
+	for _, test := range tt {
+		t.Run("", func(t *testing.T) {
+			if s := test.Style.String(); s != test.Expected {
+				t.Errorf("Expected %q, got %q", test.Expected, s)
+			}
+		})
+	}
+}
+
+func TestAdaptiveColorSequence(t *testing.T) {
+	// without an output, adaptive colors use their dark color
+	c := AdaptiveColor{
+		Light: ANSIBlack,
+		Dark:  ANSIWhite,
+	}
+
+	s := TrueColor.String("foobar").Foreground(c)
+	exp := "\x1b[37mfoobar\x1b[0m"
+	if s.String() != exp {
+		t.Errorf("Expected %q, got %q", exp, s.String())
+	}
+
+	if v := ConvertToRGB(c).Hex(); v != "#c0c0c0" {
+		t.Errorf("Expected #c0c0c0, got %s", v)
+	}
+}
@@ -1,5 +1,7 @@
 package termenv
 
+import "strings"
+
 // ANSI color codes.
 const (
 	ANSIBlack ANSIColor = iota
@@ -20,6 +22,40 @@ const (
 	ANSIBrightWhite
 )
 
+// Names of ANSI colors (0-15).
+var ansiNames = []string{
+	"black",
+	"red",
+	"green",
+	"yellow",
+	"blue",
+	"magenta",
+	"cyan",
+	"white",
+	"brightblack",
+	"brightred",
+	"brightgreen",
+	"brightyellow",
+	"brightblue",
+	"brightmagenta",
+	"brightcyan",
+	"brightwhite",
+}
+
+// ansiColorByName returns the ANSI color with the given name, e.g. "red" or
+// "brightblue". Names are case-insensitive and may contain dashes or
+// underscores, e.g. "bright-blue".
+func ansiColorByName(name string) (ANSIColor, bool) {
+	name = strings.ToLower(name)
+	name = strings.NewReplacer("-", "", "_", "").Replace(name)
+	for i, n := range ansiNames {
+		if n == name {
+			return ANSIColor(i), true
+		}
+	}
+	return 0, false
+}
+
 // RGB values of ANSI colors (0-255).
 var ansiHex = []string{
 	"#000000",
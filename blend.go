@@ -0,0 +1,120 @@
+package termenv
+
+import (
+	"math"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/rivo/uniseg"
+)
+
+// ColorSpace is a color space in which colors get interpolated.
+type ColorSpace int
+
+// Color spaces. OkLabSpace is a good default, as its blends appear
+// perceptually uniform and don't shift in hue.
+const (
+	OkLabSpace ColorSpace = iota
+	OkLchSpace
+	HclSpace
+	LabSpace
+	LuvSpace
+	LinearRGBSpace
+	RGBSpace
+)
+
+// Blend interpolates between the colors a and b in the given color space.
+// A t of 0 results in a, 1 in b, and values in between in a mix of both.
+// The result is an RGBColor, which can be downsampled with Profile.Convert.
+func Blend(a, b Color, t float64, space ColorSpace) Color {
+	t = math.Max(0, math.Min(1, t))
+	return RGBColor(blend(ConvertToRGB(a), ConvertToRGB(b), t, space).Clamped().Hex())
+}
+
+func blend(a, b colorful.Color, t float64, space ColorSpace) colorful.Color {
+	switch space {
+	case OkLchSpace:
+		return a.BlendOkLch(b, t)
+	case HclSpace:
+		return a.BlendHcl(b, t)
+	case LabSpace:
+		return a.BlendLab(b, t)
+	case LuvSpace:
+		return a.BlendLuv(b, t)
+	case LinearRGBSpace:
+		return a.BlendLinearRgb(b, t)
+	case RGBSpace:
+		return a.BlendRgb(b, t)
+	}
+	return a.BlendOkLab(b, t)
+}
+
+// Gradient returns n colors evenly spread along a gradient through the given
+// color stops, interpolated in the OkLab color space. The first and last
+// colors equal the first and last stops.
+func Gradient(stops []Color, n int) []Color {
+	if len(stops) == 0 || n <= 0 {
+		return nil
+	}
+
+	colors := make([]Color, n)
+	if len(stops) == 1 || n == 1 {
+		c := RGBColor(ConvertToRGB(stops[0]).Hex())
+		for i := range colors {
+			colors[i] = c
+		}
+		return colors
+	}
+
+	rgb := make([]colorful.Color, len(stops))
+	for i, s := range stops {
+		rgb[i] = ConvertToRGB(s)
+	}
+
+	segments := len(stops) - 1
+	for i := range colors {
+		pos := float64(i) / float64(n-1) * float64(segments)
+		seg := int(pos)
+
+		// use stops as they are, avoiding rounding errors
+		c := rgb[seg]
+		if t := pos - float64(seg); t > 0 {
+			c = blend(rgb[seg], rgb[seg+1], t, OkLabSpace)
+		}
+		colors[i] = RGBColor(c.Clamped().Hex())
+	}
+	return colors
+}
+
+// Gradient renders the string of the style with its foreground colored along
+// a gradient through the given color stops, one color per grapheme. All other
+// properties of the style are applied to the whole string. Colors get
+// converted to the style's profile.
+func (t Style) Gradient(stops ...Color) string {
+	if t.profile == Ascii || len(stops) == 0 {
+		return t.String()
+	}
+
+	var graphemes []string
+	g := uniseg.NewGraphemes(t.string)
+	for g.Next() {
+		graphemes = append(graphemes, g.Str())
+	}
+
+	colors := Gradient(stops, len(graphemes))
+
+	// adjacent graphemes often share the same color after conversion, so
+	// they get rendered together
+	var b strings.Builder
+	for i := 0; i < len(graphemes); {
+		c := t.convert(colors[i])
+		j := i + 1
+		for j < len(graphemes) && t.convert(colors[j]) == c {
+			j++
+		}
+
+		b.WriteString(t.Foreground(c).Styled(strings.Join(graphemes[i:j], "")))
+		i = j
+	}
+	return b.String()
+}
@@ -0,0 +1,82 @@
+package termenv
+
+import (
+	"testing"
+)
+
+func TestBlend(t *testing.T) {
+	a := RGBColor("#ff0000")
+	b := RGBColor("#0000ff")
+
+	tt := []struct {
+		T        float64
+		Space    ColorSpace
+		Expected Color
+	}{
+		{0, OkLabSpace, RGBColor("#ff0000")},
+		{1, OkLabSpace, RGBColor("#0000ff")},
+		{-1, OkLabSpace, RGBColor("#ff0000")},
+		{2, OkLabSpace, RGBColor("#0000ff")},
+		{0.5, RGBSpace, RGBColor("#800080")},
+		{0.5, OkLabSpace, RGBColor("#8c53a2")},
+	}
+
+	for _, test := range tt {
+		t.Run("", func(t *testing.T) {
+			if c := Blend(a, b, test.T, test.Space); c != test.Expected {
+				t.Errorf("Expected %v, got %v", test.Expected, c)
+			}
+		})
+	}
+
+	// ANSI colors get blended, too
+	if c := Blend(ANSIBlack, ANSIBrightWhite, 0.5, RGBSpace); c != RGBColor("#808080") {
+		t.Errorf("Expected %v, got %v", RGBColor("#808080"), c)
+	}
+}
+
+func TestGradient(t *testing.T) {
+	stops := []Color{RGBColor("#ff0000"), RGBColor("#00ff00"), RGBColor("#0000ff")}
+
+	if c := Gradient(stops, 0); c != nil {
+		t.Errorf("Expected no colors, got %v", c)
+	}
+	if c := Gradient(nil, 3); c != nil {
+		t.Errorf("Expected no colors, got %v", c)
+	}
+
+	c := Gradient(stops, 5)
+	if len(c) != 5 {
+		t.Fatalf("Expected 5 colors, got %d", len(c))
+	}
+	for i, exp := range []Color{stops[0], Blend(stops[0], stops[1], 0.5, OkLabSpace), stops[1], Blend(stops[1], stops[2], 0.5, OkLabSpace), stops[2]} {
+		if c[i] != exp {
+			t.Errorf("Expected color %d to be %v, got %v", i, exp, c[i])
+		}
+	}
+
+	c = Gradient(stops[:1], 3)
+	if len(c) != 3 || c[0] != stops[0] || c[2] != stops[0] {
+		t.Errorf("Expected 3 times %v, got %v", stops[0], c)
+	}
+}
+
+func TestStyleGradient(t *testing.T) {
+	s := TrueColor.String("ab").Bold()
+	exp := "\x1b[38;2;255;0;0;1ma\x1b[0m\x1b[38;2;0;0;255;1mb\x1b[0m"
+	if g := s.Gradient(RGBColor("#ff0000"), RGBColor("#0000ff")); g != exp {
+		t.Errorf("Expected %q, got %q", exp, g)
+	}
+
+	// adjacent graphemes with the same color are rendered together
+	s = ANSI.String("aaab")
+	exp = "\x1b[91ma\x1b[0m\x1b[95ma\x1b[0m\x1b[94mab\x1b[0m"
+	if g := s.Gradient(RGBColor("#ff0000"), RGBColor("#0000ff")); g != exp {
+		t.Errorf("Expected %q, got %q", exp, g)
+	}
+
+	s = Ascii.String("ab")
+	if g := s.Gradient(RGBColor("#ff0000"), RGBColor("#0000ff")); g != "ab" {
+		t.Errorf("Expected %q, got %q", "ab", g)
+	}
+}
@@ -0,0 +1,128 @@
+package termenv
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// maxCachedColors is the maximum number of entries of a colorCache.
+const maxCachedColors = 4096
+
+// colorCache is a bounded, concurrency-safe cache of color conversions and
+// sequences. Once it is full, it gets cleared, which keeps memory usage
+// bounded while still serving the colors of a typical application from the
+// cache.
+type colorCache struct {
+	mu     sync.RWMutex
+	colors map[colorKey]Color
+	seqs   map[seqKey]string
+}
+
+// colorKey identifies the conversion of a color to a profile.
+type colorKey struct {
+	profile Profile
+	color   Color
+}
+
+// seqKey identifies the sequence of either an RGBColor or an RGB color for a
+// given prefix, i.e. Foreground, Background or UnderlineColor. It doesn't hold
+// a Color, as boxing colors in an interface would allocate.
+type seqKey struct {
+	prefix string
+	hex    RGBColor
+	rgb    RGB
+	parsed bool
+}
+
+// defaultColorCache is used by all conversions that don't depend on a palette
+// or quantizer of an Output.
+var defaultColorCache = newColorCache()
+
+func newColorCache() *colorCache {
+	return &colorCache{
+		colors: make(map[colorKey]Color),
+		seqs:   make(map[seqKey]string),
+	}
+}
+
+// convert converts c to the profile p, like Profile.convert. Results for
+// colors that are expensive to convert are cached.
+func (cc *colorCache) convert(p Profile, c Color, pal *Palette, q Quantizer) Color {
+	switch c.(type) {
+	case RGBColor, RGB, ANSI256Color:
+	default:
+		return p.convert(c, pal, q)
+	}
+
+	k := colorKey{p, c}
+	cc.mu.RLock()
+	v, ok := cc.colors[k]
+	cc.mu.RUnlock()
+	if ok {
+		return v
+	}
+
+	v = p.convert(c, pal, q)
+
+	cc.mu.Lock()
+	if len(cc.colors) >= maxCachedColors {
+		cc.colors = make(map[colorKey]Color)
+	}
+	cc.colors[k] = v
+	cc.mu.Unlock()
+
+	return v
+}
+
+// sequence returns the sequence of the color identified by k.
+func (cc *colorCache) sequence(k seqKey) string {
+	cc.mu.RLock()
+	seq, ok := cc.seqs[k]
+	cc.mu.RUnlock()
+	if ok {
+		return seq
+	}
+
+	if k.parsed {
+		seq = fmt.Sprintf("%s;2;%d;%d;%d", k.prefix, k.rgb.R, k.rgb.G, k.rgb.B)
+	} else if f, err := colorful.Hex(string(k.hex)); err == nil {
+		seq = fmt.Sprintf("%s;2;%d;%d;%d", k.prefix, uint8(f.R*255), uint8(f.G*255), uint8(f.B*255)) //nolint:mnd
+	}
+
+	cc.mu.Lock()
+	if len(cc.seqs) >= maxCachedColors {
+		cc.seqs = make(map[seqKey]string)
+	}
+	cc.seqs[k] = seq
+	cc.mu.Unlock()
+
+	return seq
+}
+
+// Precomputed sequences of the ANSI colors.
+var (
+	ansiForegroundSeqs    [16]string
+	ansiBackgroundSeqs    [16]string
+	ansi256ForegroundSeqs [256]string
+	ansi256BackgroundSeqs [256]string
+	ansi256UnderlineSeqs  [256]string
+)
+
+//nolint:mnd
+func init() {
+	for i := range ansiForegroundSeqs {
+		base := 30
+		if i >= 8 {
+			base = 90 - 8
+		}
+		ansiForegroundSeqs[i] = fmt.Sprintf("%d", base+i)
+		ansiBackgroundSeqs[i] = fmt.Sprintf("%d", base+10+i)
+	}
+	for i := range ansi256ForegroundSeqs {
+		ansi256ForegroundSeqs[i] = fmt.Sprintf("%s;5;%d", Foreground, i)
+		ansi256BackgroundSeqs[i] = fmt.Sprintf("%s;5;%d", Background, i)
+		ansi256UnderlineSeqs[i] = fmt.Sprintf("%s;5;%d", UnderlineColor, i)
+	}
+}
@@ -0,0 +1,184 @@
+package termenv
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+)
+
+func TestRGB(t *testing.T) {
+	c, err := ParseRGB("#abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c != (RGB{0xab, 0xcd, 0xef}) {
+		t.Errorf("Expected %v, got %v", RGB{0xab, 0xcd, 0xef}, c)
+	}
+	if c.String() != "#abcdef" {
+		t.Errorf("Expected #abcdef, got %s", c.String())
+	}
+
+	if _, err := ParseRGB("#abcdeg"); err == nil {
+		t.Errorf("Expected an error for an invalid color")
+	}
+
+	var u RGB
+	if err := u.UnmarshalText([]byte("#abcdef")); err != nil || u != c {
+		t.Errorf("Expected %v, got %v (%v)", c, u, err)
+	}
+	if b, _ := c.MarshalText(); string(b) != "#abcdef" {
+		t.Errorf("Expected #abcdef, got %s", b)
+	}
+
+	exp := "38;2;171;205;239"
+	if s := c.Sequence(false); s != exp {
+		t.Errorf("Expected %s, got %s", exp, s)
+	}
+
+	// RGB colors convert just like their RGBColor counterparts
+	for _, p := range []Profile{TrueColor, ANSI256, ANSI, Ascii} {
+		exp := p.Convert(RGBColor("#abcdef"))
+		if p == TrueColor {
+			exp = c
+		}
+		if v := p.Convert(c); v != exp {
+			t.Errorf("%s: expected %#v, got %#v", p.Name(), exp, v)
+		}
+	}
+}
+
+func TestColorSequenceTables(t *testing.T) {
+	for i := 0; i < 16; i++ {
+		fg, bg := 30+i, 40+i
+		if i >= 8 {
+			fg, bg = 90+i-8, 100+i-8
+		}
+		if s := ANSIColor(i).Sequence(false); s != fmt.Sprint(fg) {
+			t.Errorf("Expected %d, got %s", fg, s)
+		}
+		if s := ANSIColor(i).Sequence(true); s != fmt.Sprint(bg) {
+			t.Errorf("Expected %d, got %s", bg, s)
+		}
+	}
+
+	for i := 0; i < 256; i++ {
+		c := ANSI256Color(i)
+		if s, exp := c.Sequence(false), fmt.Sprintf("38;5;%d", i); s != exp {
+			t.Errorf("Expected %s, got %s", exp, s)
+		}
+		if s, exp := c.Sequence(true), fmt.Sprintf("48;5;%d", i); s != exp {
+			t.Errorf("Expected %s, got %s", exp, s)
+		}
+		if s, exp := c.UnderlineSequence(), fmt.Sprintf("58;5;%d", i); s != exp {
+			t.Errorf("Expected %s, got %s", exp, s)
+		}
+	}
+}
+
+func TestColorCacheBounded(t *testing.T) {
+	cc := newColorCache()
+	for i := 0; i < maxCachedColors*2; i++ {
+		c := RGB{uint8(i >> 16), uint8(i >> 8), uint8(i)}
+		if v := cc.convert(ANSI256, c, nil, nil); v != ANSI256.convert(c, nil, nil) {
+			t.Fatalf("Expected %v, got %v", ANSI256.convert(c, nil, nil), v)
+		}
+		cc.sequence(seqKey{prefix: Foreground, rgb: c, parsed: true})
+	}
+
+	if len(cc.colors) > maxCachedColors || len(cc.seqs) > maxCachedColors {
+		t.Errorf("Expected at most %d entries, got %d colors and %d sequences",
+			maxCachedColors, len(cc.colors), len(cc.seqs))
+	}
+}
+
+func TestColorCacheConcurrency(t *testing.T) {
+	o := NewOutput(io.Discard, WithProfile(ANSI), WithQuantizer(OkLabQuantizer{}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				c := RGBColor(fmt.Sprintf("#%02x%02x%02x", i, j%256, j/256))
+				_ = o.Convert(c)
+				_ = ANSI256.Convert(c)
+				_ = c.Sequence(j%2 == 0)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestConvertAllocations(t *testing.T) {
+	var c Color = RGBColor("#abcdef")
+	ANSI256.Convert(c)
+	c.Sequence(false)
+
+	if n := testing.AllocsPerRun(100, func() {
+		ANSI256.Convert(c)
+		c.Sequence(false)
+		ANSI256Color(69).Sequence(true)
+	}); n != 0 {
+		t.Errorf("Expected no allocations, got %.0f", n)
+	}
+}
+
+func BenchmarkConvertUncached(b *testing.B) {
+	var c Color = RGBColor("#abcdef")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ANSI256.convert(c, nil, nil)
+	}
+}
+
+func BenchmarkConvert(b *testing.B) {
+	var c Color = RGBColor("#abcdef")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ANSI256.Convert(c)
+	}
+}
+
+func BenchmarkConvertRGB(b *testing.B) {
+	var c Color = RGB{0xab, 0xcd, 0xef}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ANSI256.Convert(c)
+	}
+}
+
+func BenchmarkConvertANSI(b *testing.B) {
+	var c Color = RGBColor("#abcdef")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ANSI.Convert(c)
+	}
+}
+
+func BenchmarkSequenceUncached(b *testing.B) {
+	cc := newColorCache()
+	k := seqKey{prefix: Foreground, hex: RGBColor("#abcdef")}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		cc.seqs = map[seqKey]string{}
+		cc.sequence(k)
+	}
+}
+
+func BenchmarkSequence(b *testing.B) {
+	var c Color = RGBColor("#abcdef")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.Sequence(false)
+	}
+}
+
+func BenchmarkANSI256Sequence(b *testing.B) {
+	var c Color = ANSI256Color(69)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.Sequence(false)
+	}
+}
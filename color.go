@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strconv"
 	"strings"
 
 	"github.com/lucasb-eyer/go-colorful"
@@ -12,10 +13,11 @@ import (
 // ErrInvalidColor gets returned when a color is invalid.
 var ErrInvalidColor = errors.New("invalid color")
 
-// Foreground and Background sequence codes.
+// Foreground, Background and UnderlineColor sequence codes.
 const (
-	Foreground = "38"
-	Background = "48"
+	Foreground     = "38"
+	Background     = "48"
+	UnderlineColor = "58"
 )
 
 // Color is an interface implemented by all colors that can be converted to an
@@ -49,16 +51,189 @@ func (c ANSI256Color) String() string {
 // RGBColor is a hex-encoded color, e.g. "#abcdef".
 type RGBColor string
 
-// ConvertToRGB converts a Color to a colorful.Color.
+// ParseColor parses a color from a string. Valid inputs are:
+//
+//   - hex colors, e.g. "#abcdef" or "#abc"
+//   - ANSI color codes (0-15, 16-255)
+//   - names of ANSI colors, e.g. "red" or "brightblue"
+//   - X11 and CSS color names, e.g. "cornflowerblue"
+//   - CSS functional notations, e.g. "rgb(255, 136, 0)" or "hsl(32, 100%, 50%)"
+//   - X11 color specs, e.g. "rgb:ffff/8888/0000"
+//
+// Names are case-insensitive. Where a name refers to both an ANSI color and a
+// named color, e.g. "red", the ANSI color wins, as it respects the terminal's
+// color scheme.
+func ParseColor(s string) (Color, error) {
+	s = strings.TrimSpace(s)
+	name := strings.ToLower(s)
+
+	switch {
+	case s == "":
+		return nil, ErrInvalidColor
+
+	case strings.HasPrefix(s, "#"):
+		c, err := colorful.Hex(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidColor, s)
+		}
+		return RGBColor(c.Hex()), nil
+
+	case strings.HasPrefix(name, "rgb:"):
+		c, err := xParseColor(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidColor, s)
+		}
+		return c, nil
+
+	case strings.HasSuffix(name, ")"):
+		c, err := parseColorFunc(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidColor, s)
+		}
+		return c, nil
+	}
+
+	if c, ok := ansiColorByName(name); ok {
+		return c, nil
+	}
+	if c, ok := colorNames[strings.ReplaceAll(name, " ", "")]; ok {
+		return c, nil
+	}
+
+	i, err := strconv.Atoi(s)
+	if err != nil || i < 0 || i > 255 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidColor, s)
+	}
+	if i < 16 { //nolint:mnd
+		return ANSIColor(i), nil
+	}
+	return ANSI256Color(i), nil
+}
+
+// parseColorFunc parses the CSS functional notations rgb(), rgba(), hsl() and
+// hsla(). Components may be separated by commas or spaces, and an alpha
+// component is ignored.
+//
+//nolint:mnd
+func parseColorFunc(s string) (RGBColor, error) {
+	i := strings.Index(s, "(")
+	if i < 0 {
+		return "", ErrInvalidColor
+	}
+
+	fn := s[:i]
+	args := strings.FieldsFunc(s[i+1:len(s)-1], func(r rune) bool {
+		return r == ',' || r == '/' || r == ' ' || r == '\t'
+	})
+	if len(args) != 3 && len(args) != 4 {
+		return "", ErrInvalidColor
+	}
+
+	var c colorful.Color
+	switch fn {
+	case "rgb", "rgba":
+		var rgb [3]float64
+		for i, arg := range args[:3] {
+			v, err := parseColorComponent(arg, 255)
+			if err != nil {
+				return "", err
+			}
+			rgb[i] = v
+		}
+		c = colorful.Color{R: rgb[0], G: rgb[1], B: rgb[2]}
+
+	case "hsl", "hsla":
+		h, err := strconv.ParseFloat(strings.TrimSuffix(args[0], "deg"), 64)
+		if err != nil {
+			return "", ErrInvalidColor
+		}
+		if !strings.HasSuffix(args[1], "%") || !strings.HasSuffix(args[2], "%") {
+			return "", ErrInvalidColor
+		}
+		sat, err := parseColorComponent(args[1], 100)
+		if err != nil {
+			return "", err
+		}
+		l, err := parseColorComponent(args[2], 100)
+		if err != nil {
+			return "", err
+		}
+		c = colorful.Hsl(math.Mod(math.Mod(h, 360)+360, 360), sat, l)
+
+	default:
+		return "", ErrInvalidColor
+	}
+
+	return RGBColor(c.Clamped().Hex()), nil
+}
+
+// parseColorComponent parses a number between 0 and scale, or a percentage, and
+// returns it scaled to the range 0-1.
+func parseColorComponent(s string, scale float64) (float64, error) {
+	if strings.HasSuffix(s, "%") {
+		s = strings.TrimSuffix(s, "%")
+		scale = 100
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 || v > scale {
+		return 0, ErrInvalidColor
+	}
+	return v / scale, nil
+}
+
+// RGB is an RGB color with pre-parsed components. Unlike RGBColor, it doesn't
+// need to be parsed whenever it gets converted, which makes it a good fit for
+// hot rendering paths.
+type RGB struct {
+	R, G, B uint8
+}
+
+// ParseRGB parses a hex color, e.g. "#abcdef" or "#abc", into an RGB color.
+func ParseRGB(s string) (RGB, error) {
+	c, err := colorful.Hex(s)
+	if err != nil {
+		return RGB{}, fmt.Errorf("%w: %q", ErrInvalidColor, s)
+	}
+
+	r, g, b := c.RGB255()
+	return RGB{r, g, b}, nil
+}
+
+func (c RGB) String() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// colorful returns the color as a colorful.Color.
+//
+//nolint:mnd
+func (c RGB) colorful() colorful.Color {
+	return colorful.Color{
+		R: float64(c.R) / 255,
+		G: float64(c.G) / 255,
+		B: float64(c.B) / 255,
+	}
+}
+
+// ConvertToRGB converts a Color to a colorful.Color. Adaptive colors use their
+// Dark color, while complete colors use their value with the highest
+// fidelity. Resolve adaptive colors with Output.Convert first to take the
+// terminal's background color into account.
 func ConvertToRGB(c Color) colorful.Color {
 	var hex string
 	switch v := c.(type) {
 	case RGBColor:
 		hex = string(v)
+	case RGB:
+		return v.colorful()
 	case ANSIColor:
 		hex = ansiHex[v]
 	case ANSI256Color:
 		hex = ansiHex[v]
+	case AdaptiveColor:
+		return ConvertToRGB(v.Dark)
+	case CompleteColor:
+		return ConvertToRGB(v.best())
 	}
 
 	ch, _ := colorful.Hex(hex)
@@ -75,6 +250,13 @@ func (c NoColor) Sequence(_ bool) string {
 //nolint:mnd
 func (c ANSIColor) Sequence(bg bool) string {
 	col := int(c)
+	if col >= 0 && col < len(ansiForegroundSeqs) {
+		if bg {
+			return ansiBackgroundSeqs[col]
+		}
+		return ansiForegroundSeqs[col]
+	}
+
 	bgMod := func(c int) int {
 		if bg {
 			return c + 10
@@ -90,6 +272,13 @@ func (c ANSIColor) Sequence(bg bool) string {
 
 // Sequence returns the ANSI Sequence for the color.
 func (c ANSI256Color) Sequence(bg bool) string {
+	if c >= 0 && int(c) < len(ansi256ForegroundSeqs) {
+		if bg {
+			return ansi256BackgroundSeqs[c]
+		}
+		return ansi256ForegroundSeqs[c]
+	}
+
 	prefix := Foreground
 	if bg {
 		prefix = Background
@@ -99,16 +288,54 @@ func (c ANSI256Color) Sequence(bg bool) string {
 
 // Sequence returns the ANSI Sequence for the color.
 func (c RGBColor) Sequence(bg bool) string {
-	f, err := colorful.Hex(string(c))
-	if err != nil {
-		return ""
+	prefix := Foreground
+	if bg {
+		prefix = Background
 	}
+	return defaultColorCache.sequence(seqKey{prefix: prefix, hex: c})
+}
 
+// Sequence returns the ANSI Sequence for the color.
+func (c RGB) Sequence(bg bool) string {
 	prefix := Foreground
 	if bg {
 		prefix = Background
 	}
-	return fmt.Sprintf("%s;2;%d;%d;%d", prefix, uint8(f.R*255), uint8(f.G*255), uint8(f.B*255)) //nolint:mnd
+	return defaultColorCache.sequence(seqKey{prefix: prefix, rgb: c, parsed: true})
+}
+
+// UnderlineSequence returns the ANSI Sequence for using the color as underline
+// color.
+func (c NoColor) UnderlineSequence() string {
+	return ""
+}
+
+// UnderlineSequence returns the ANSI Sequence for using the color as underline
+// color. As there are no dedicated sequences for the 16 ANSI colors, the
+// 256-color palette gets used.
+func (c ANSIColor) UnderlineSequence() string {
+	return ANSI256Color(c).UnderlineSequence()
+}
+
+// UnderlineSequence returns the ANSI Sequence for using the color as underline
+// color.
+func (c ANSI256Color) UnderlineSequence() string {
+	if c >= 0 && int(c) < len(ansi256UnderlineSeqs) {
+		return ansi256UnderlineSeqs[c]
+	}
+	return fmt.Sprintf("%s;5;%d", UnderlineColor, c)
+}
+
+// UnderlineSequence returns the ANSI Sequence for using the color as underline
+// color.
+func (c RGBColor) UnderlineSequence() string {
+	return defaultColorCache.sequence(seqKey{prefix: UnderlineColor, hex: c})
+}
+
+// UnderlineSequence returns the ANSI Sequence for using the color as underline
+// color.
+func (c RGB) UnderlineSequence() string {
+	return defaultColorCache.sequence(seqKey{prefix: UnderlineColor, rgb: c, parsed: true})
 }
 
 func xTermColor(s string) (RGBColor, error) {
@@ -140,24 +367,6 @@ func xTermColor(s string) (RGBColor, error) {
 	return RGBColor(hex), nil
 }
 
-func ansi256ToANSIColor(c ANSI256Color) ANSIColor {
-	var r int
-	md := math.MaxFloat64
-
-	h, _ := colorful.Hex(ansiHex[c])
-	for i := 0; i <= 15; i++ {
-		hb, _ := colorful.Hex(ansiHex[i])
-		d := h.DistanceHSLuv(hb)
-
-		if d < md {
-			md = d
-			r = i
-		}
-	}
-
-	return ANSIColor(r)
-}
-
 //nolint:mnd
 func hexToANSI256Color(c colorful.Color) ANSI256Color {
 	v2ci := func(v float64) int {
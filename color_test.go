@@ -1,6 +1,9 @@
 package termenv
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 func TestXTermColor(t *testing.T) {
 	var tests = []struct {
@@ -62,3 +65,59 @@ func TestXTermColor(t *testing.T) {
 		})
 	}
 }
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		input string
+		color Color
+		valid bool
+	}{
+		{"#abcdef", RGBColor("#abcdef"), true},
+		{"#ABCDEF", RGBColor("#abcdef"), true},
+		{"#abc", RGBColor("#aabbcc"), true},
+		{"7", ANSIColor(7), true},
+		{"196", ANSI256Color(196), true},
+		{"red", ANSIRed, true},
+		{"BrightBlue", ANSIBrightBlue, true},
+		{"bright-white", ANSIBrightWhite, true},
+		{"cornflowerblue", RGBColor("#6495ed"), true},
+		{"Cornflower Blue", RGBColor("#6495ed"), true},
+		{"grey50", RGBColor("#7f7f7f"), true},
+		{"gray", RGBColor("#808080"), true},
+		{"rgb(255, 136, 0)", RGBColor("#ff8800"), true},
+		{"rgb(100% 0% 50%)", RGBColor("#ff0080"), true},
+		{"rgba(255,136,0,0.5)", RGBColor("#ff8800"), true},
+		{"hsl(120, 100%, 25%)", RGBColor("#008000"), true},
+		{"hsl(-240deg 100% 50%)", RGBColor("#00ff00"), true},
+		{"rgb:ffff/8888/0000", RGBColor("#ff8800"), true},
+		{"rgb:f/8/0", RGBColor("#ff8800"), true},
+		{"", nil, false},
+		{"#abcd", nil, false},
+		{"256", nil, false},
+		{"-1", nil, false},
+		{"notacolor", nil, false},
+		{"rgb(256, 0, 0)", nil, false},
+		{"rgb(1, 2)", nil, false},
+		{"hsl(120, 100, 25)", nil, false},
+		{"cmyk(0, 0, 0, 0)", nil, false},
+		{"rgb:ff/ff", nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			c, err := ParseColor(test.input)
+			if !test.valid {
+				if !errors.Is(err, ErrInvalidColor) {
+					t.Errorf("Expected error %v, got %v", ErrInvalidColor, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if c != test.color {
+				t.Errorf("Expected %#v, got %#v", test.color, c)
+			}
+		})
+	}
+}
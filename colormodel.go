@@ -0,0 +1,123 @@
+package termenv
+
+import (
+	"image/color"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// RGBA implements color.Color. NoColor is fully transparent.
+func (c NoColor) RGBA() (r, g, b, a uint32) {
+	return 0, 0, 0, 0
+}
+
+// RGBA implements color.Color, using xterm's default palette. Use
+// Output.ColorPalette to take the terminal's actual palette into account.
+func (c ANSIColor) RGBA() (r, g, b, a uint32) {
+	return ANSI256Color(c).RGBA()
+}
+
+// RGBA implements color.Color, using xterm's default palette.
+func (c ANSI256Color) RGBA() (r, g, b, a uint32) {
+	if c < 0 || int(c) >= len(ansiColors) {
+		return 0, 0, 0, 0
+	}
+	return ansiColors[c].RGBA()
+}
+
+// RGBA implements color.Color. Invalid colors are fully transparent.
+func (c RGBColor) RGBA() (r, g, b, a uint32) {
+	h, err := colorful.Hex(string(c))
+	if err != nil {
+		return 0, 0, 0, 0
+	}
+	return h.RGBA()
+}
+
+// RGBA implements color.Color.
+func (c RGB) RGBA() (r, g, b, a uint32) {
+	return color.RGBA{c.R, c.G, c.B, 0xff}.RGBA()
+}
+
+// RGBA implements color.Color, using the Dark color.
+func (c AdaptiveColor) RGBA() (r, g, b, a uint32) {
+	return ConvertToRGB(c).RGBA()
+}
+
+// RGBA implements color.Color, using the value with the highest fidelity.
+func (c CompleteColor) RGBA() (r, g, b, a uint32) {
+	return ConvertToRGB(c).RGBA()
+}
+
+// ColorModel returns a color.Model converting colors to the Profile. The
+// resulting colors are termenv Colors, e.g. ANSIColor for the ANSI profile.
+// Fully transparent colors, and all colors on the Ascii profile, become
+// NoColor.
+func (p Profile) ColorModel() color.Model {
+	return colorModel(p.Convert)
+}
+
+// ColorModel returns a color.Model converting colors to the Output's Profile,
+// taking its palette and quantizer into account.
+func (o Output) ColorModel() color.Model {
+	return colorModel(o.Convert)
+}
+
+func colorModel(convert func(Color) Color) color.Model {
+	return color.ModelFunc(func(c color.Color) color.Color {
+		tc, ok := c.(Color)
+		if !ok {
+			col, ok := colorful.MakeColor(c)
+			if !ok {
+				return NoColor{}
+			}
+			r, g, b := col.Clamped().RGB255()
+			tc = RGB{r, g, b}
+		}
+
+		if v, ok := convert(tc).(color.Color); ok {
+			return v
+		}
+		return NoColor{}
+	})
+}
+
+// ColorPalette returns the colors of the Profile as a color.Palette, e.g. for
+// use with image.Paletted. Indices in the palette equal the color codes.
+// TrueColor and Ascii don't have a palette, and return nil.
+func (p Profile) ColorPalette() color.Palette {
+	return colorPalette(p, nil)
+}
+
+// ColorPalette returns the colors of the Output's Profile as a color.Palette,
+// like Profile.ColorPalette. The 16 ANSI colors are taken from the Output's
+// palette, if set.
+func (o Output) ColorPalette() color.Palette {
+	return colorPalette(o.Profile, o.palette)
+}
+
+//nolint:mnd
+func colorPalette(p Profile, pal *Palette) color.Palette {
+	var n int
+	switch p {
+	case ANSI256:
+		n = 256
+	case ANSI:
+		n = 16
+	default:
+		return nil
+	}
+
+	cp := make(color.Palette, n)
+	for i := range cp {
+		switch {
+		case i < 16 && pal != nil:
+			cp[i] = pal[i]
+		case i < 16:
+			cp[i] = ANSIColor(i)
+		default:
+			cp[i] = ANSI256Color(i)
+		}
+	}
+	return cp
+}
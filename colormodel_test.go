@@ -0,0 +1,84 @@
+package termenv
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"testing"
+)
+
+func TestColorRGBA(t *testing.T) {
+	tt := []struct {
+		Color    color.Color
+		Expected color.RGBA
+	}{
+		{NoColor{}, color.RGBA{}},
+		{ANSIRed, color.RGBA{0x80, 0, 0, 0xff}},
+		{ANSI256Color(208), color.RGBA{0xff, 0x87, 0, 0xff}},
+		{RGBColor("#abcdef"), color.RGBA{0xab, 0xcd, 0xef, 0xff}},
+		{RGBColor("invalid"), color.RGBA{}},
+		{RGB{0xab, 0xcd, 0xef}, color.RGBA{0xab, 0xcd, 0xef, 0xff}},
+		{CompleteColor{TrueColor: RGBColor("#abcdef"), ANSI: ANSIRed}, color.RGBA{0xab, 0xcd, 0xef, 0xff}},
+	}
+
+	for _, test := range tt {
+		t.Run("", func(t *testing.T) {
+			c := color.RGBAModel.Convert(test.Color)
+			if c != test.Expected {
+				t.Errorf("%#v: expected %v, got %v", test.Color, test.Expected, c)
+			}
+		})
+	}
+}
+
+func TestColorModel(t *testing.T) {
+	orange := color.RGBA{0xff, 0x88, 0, 0xff}
+
+	tt := []struct {
+		Model    color.Model
+		Color    color.Color
+		Expected color.Color
+	}{
+		{TrueColor.ColorModel(), orange, RGB{0xff, 0x88, 0}},
+		{ANSI256.ColorModel(), orange, ANSI256Color(208)},
+		{ANSI.ColorModel(), orange, ANSIBrightRed},
+		{Ascii.ColorModel(), orange, NoColor{}},
+		{ANSI256.ColorModel(), color.Transparent, NoColor{}},
+		{ANSI.ColorModel(), ANSI256Color(208), ANSIBrightRed},
+		{NewOutput(io.Discard, WithProfile(ANSI), WithQuantizer(RGBQuantizer{})).ColorModel(), orange, ANSIBrightYellow},
+	}
+
+	for _, test := range tt {
+		t.Run("", func(t *testing.T) {
+			if c := test.Model.Convert(test.Color); c != test.Expected {
+				t.Errorf("Expected %#v, got %#v", test.Expected, c)
+			}
+		})
+	}
+}
+
+func TestColorPalette(t *testing.T) {
+	if p := TrueColor.ColorPalette(); p != nil {
+		t.Errorf("Expected no palette, got %d colors", len(p))
+	}
+	if p := ANSI256.ColorPalette(); len(p) != 256 || p[1] != ANSIRed || p[208] != ANSI256Color(208) {
+		t.Errorf("Unexpected ANSI256 palette %v", p)
+	}
+
+	pal := DefaultPalette()
+	pal[ANSIRed] = RGBColor("#ff0000")
+	o := NewOutput(io.Discard, WithProfile(ANSI), WithPalette(pal))
+	p := o.ColorPalette()
+	if len(p) != 16 || p[1] != RGBColor("#ff0000") {
+		t.Errorf("Unexpected ANSI palette %v", p)
+	}
+
+	// draw an image with the terminal's palette
+	src := image.NewUniform(color.RGBA{0xf0, 0x10, 0x10, 0xff})
+	dst := image.NewPaletted(image.Rect(0, 0, 1, 1), p)
+	draw.Draw(dst, dst.Bounds(), src, image.Point{}, draw.Src)
+	if i := dst.ColorIndexAt(0, 0); i != uint8(ANSIRed) {
+		t.Errorf("Expected color index %d, got %d", ANSIRed, i)
+	}
+}
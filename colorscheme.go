@@ -0,0 +1,127 @@
+package termenv
+
+import (
+	"context"
+	"strings"
+)
+
+// ColorScheme is the color scheme preferred by the terminal, usually following
+// the system's light or dark mode.
+type ColorScheme int
+
+// Color schemes.
+const (
+	// UnknownColorScheme means the terminal didn't report its color scheme.
+	UnknownColorScheme ColorScheme = iota
+	// DarkColorScheme means the terminal prefers light text on a dark
+	// background.
+	DarkColorScheme
+	// LightColorScheme means the terminal prefers dark text on a light
+	// background.
+	LightColorScheme
+)
+
+// String returns the color scheme as a string.
+func (c ColorScheme) String() string {
+	switch c {
+	case DarkColorScheme:
+		return "dark"
+	case LightColorScheme:
+		return "light"
+	}
+	return "unknown"
+}
+
+// ColorScheme asks the terminal for its preferred color scheme. Terminals
+// that don't support color scheme reports return ErrStatusReport.
+func (o *Output) ColorScheme() (ColorScheme, error) {
+	replies, err := o.Query(context.Background(), CSI+RequestColorSchemeSeq, func(r Reply) bool {
+		_, ok := parseColorSchemeReply(r)
+		return ok
+	})
+	if err != nil {
+		return UnknownColorScheme, err
+	}
+	if len(replies) == 0 {
+		return UnknownColorScheme, ErrStatusReport
+	}
+
+	c, _ := parseColorSchemeReply(replies[0])
+	return c, nil
+}
+
+// NotifyColorScheme enables color scheme updates and sends the terminal's
+// color scheme on the returned channel whenever it changes, until ctx is done.
+// Updates get disabled and the channel gets closed afterwards.
+//
+// While subscribed, all input of the terminal is consumed, so this is only
+// useful for programs that don't read from the terminal otherwise. Programs
+// with an input loop of their own should call EnableColorSchemeUpdates and
+// use ParseColorSchemeReport instead.
+func (o *Output) NotifyColorScheme(ctx context.Context) (<-chan ColorScheme, error) {
+	tty := o.TTY()
+	if tty == nil {
+		return nil, ErrStatusReport
+	}
+
+	restore, err := o.disableEcho(tty)
+	if err != nil {
+		return nil, err
+	}
+
+	o.EnableColorSchemeUpdates()
+
+	ch := make(chan ColorScheme)
+	go func() {
+		defer close(ch)
+		defer restore()
+		defer o.DisableColorSchemeUpdates()
+
+		for {
+			r, err := readReply(func() (byte, error) {
+				return o.readByte(ctx, tty)
+			})
+			if err != nil {
+				return
+			}
+
+			c, ok := parseColorSchemeReply(r)
+			if !ok {
+				continue
+			}
+
+			select {
+			case ch <- c:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// ParseColorSchemeReport parses a color scheme report sent by the terminal,
+// e.g. "\x1b[?997;1n". It reports false if seq is no such report.
+func ParseColorSchemeReport(seq string) (ColorScheme, bool) {
+	if !strings.HasPrefix(seq, CSI) {
+		return UnknownColorScheme, false
+	}
+	return parseColorSchemeReply(Reply{Type: CSIReply, Payload: strings.TrimPrefix(seq, CSI)})
+}
+
+// parseColorSchemeReply parses the payload of a color scheme report:
+// "?997;1n" for dark and "?997;2n" for light color schemes.
+func parseColorSchemeReply(r Reply) (ColorScheme, bool) {
+	if r.Type != CSIReply {
+		return UnknownColorScheme, false
+	}
+
+	switch r.Payload {
+	case "?997;1n":
+		return DarkColorScheme, true
+	case "?997;2n":
+		return LightColorScheme, true
+	}
+	return UnknownColorScheme, false
+}
@@ -0,0 +1,26 @@
+package termenv
+
+import "testing"
+
+func TestParseColorSchemeReport(t *testing.T) {
+	tt := []struct {
+		Input    string
+		Expected ColorScheme
+		Valid    bool
+	}{
+		{"\x1b[?997;1n", DarkColorScheme, true},
+		{"\x1b[?997;2n", LightColorScheme, true},
+		{"\x1b[?997;3n", UnknownColorScheme, false},
+		{"\x1b[?996n", UnknownColorScheme, false},
+		{"?997;1n", UnknownColorScheme, false},
+	}
+
+	for _, test := range tt {
+		t.Run("", func(t *testing.T) {
+			c, ok := ParseColorSchemeReport(test.Input)
+			if c != test.Expected || ok != test.Valid {
+				t.Errorf("Expected %s, %t, got %s, %t", test.Expected, test.Valid, c, ok)
+			}
+		})
+	}
+}
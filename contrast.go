@@ -0,0 +1,141 @@
+package termenv
+
+import (
+	"math"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// MinimumContrastRatio is the contrast ratio WCAG 2 level AA requires for
+// normal text.
+const MinimumContrastRatio = 4.5
+
+// ContrastRatio returns the WCAG 2 contrast ratio between the colors a and b,
+// ranging from 1 (no contrast) to 21 (black and white). The order of the
+// colors doesn't matter.
+func ContrastRatio(a, b Color) float64 {
+	return contrastRatio(ConvertToRGB(a), ConvertToRGB(b))
+}
+
+func contrastRatio(a, b colorful.Color) float64 {
+	la, lb := relativeLuminance(a), relativeLuminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05) //nolint:mnd
+}
+
+// relativeLuminance returns the relative luminance of c as defined by WCAG 2.
+//
+//nolint:mnd
+func relativeLuminance(c colorful.Color) float64 {
+	r, g, b := c.Clamped().LinearRgb()
+	return 0.2126*r + 0.7152*g + 0.0722*b
+}
+
+// APCAContrast returns the lightness contrast (Lc) of text on a background
+// color, as defined by the Accessible Perceptual Contrast Algorithm (APCA
+// 0.0.98G). It ranges from about -108 to 106. Positive values mean dark text
+// on a light background, negative values light text on a dark background.
+// Values around ±60 and above are considered readable for body text.
+func APCAContrast(text, bg Color) float64 {
+	return apcaContrast(ConvertToRGB(text), ConvertToRGB(bg))
+}
+
+//nolint:mnd
+func apcaContrast(text, bg colorful.Color) float64 {
+	const (
+		blkThrs    = 0.022
+		blkClmp    = 1.414
+		deltaYMin  = 0.0005
+		scale      = 1.14
+		loClip     = 0.1
+		loOffset   = 0.027
+		normBG     = 0.56
+		normTXT    = 0.57
+		revTXT     = 0.62
+		revBG      = 0.65
+		mainTRC    = 2.4
+		redCoeff   = 0.2126729
+		greenCoeff = 0.7151522
+		blueCoeff  = 0.0721750
+	)
+
+	luminance := func(c colorful.Color) float64 {
+		c = c.Clamped()
+		y := redCoeff*math.Pow(c.R, mainTRC) +
+			greenCoeff*math.Pow(c.G, mainTRC) +
+			blueCoeff*math.Pow(c.B, mainTRC)
+
+		// soft clamp near black
+		if y < blkThrs {
+			y += math.Pow(blkThrs-y, blkClmp)
+		}
+		return y
+	}
+
+	yt, yb := luminance(text), luminance(bg)
+	if math.Abs(yb-yt) < deltaYMin {
+		return 0
+	}
+
+	var lc float64
+	if yb > yt {
+		// dark text on a light background
+		sapc := (math.Pow(yb, normBG) - math.Pow(yt, normTXT)) * scale
+		if sapc >= loClip {
+			lc = sapc - loOffset
+		}
+	} else {
+		// light text on a dark background
+		sapc := (math.Pow(yb, revBG) - math.Pow(yt, revTXT)) * scale
+		if sapc <= -loClip {
+			lc = sapc + loOffset
+		}
+	}
+	return lc * 100
+}
+
+// ReadableForeground returns the candidate with the highest contrast ratio
+// on the background color bg, as long as it meets MinimumContrastRatio.
+// Otherwise, or if there are no candidates, it returns black or white,
+// whichever is more readable.
+func ReadableForeground(bg Color, candidates ...Color) Color {
+	return readableForeground(ConvertToRGB, bg, candidates)
+}
+
+// ReadableForeground returns the candidate with the highest contrast ratio
+// on the background color bg, like the package-level ReadableForeground. If
+// bg is nil or NoColor, the terminal's background color is used. ANSI colors
+// are resolved using the Output's palette.
+func (o *Output) ReadableForeground(bg Color, candidates ...Color) Color {
+	switch bg.(type) {
+	case nil, NoColor:
+		bg = o.BackgroundColor()
+	}
+	return readableForeground(o.convertToRGB, bg, candidates)
+}
+
+func readableForeground(toRGB func(Color) colorful.Color, bg Color, candidates []Color) Color {
+	b := toRGB(bg)
+
+	var best Color
+	bestRatio := 0.0
+	for _, c := range candidates {
+		if c == nil {
+			continue
+		}
+		if r := contrastRatio(toRGB(c), b); r > bestRatio {
+			best, bestRatio = c, r
+		}
+	}
+	if best != nil && bestRatio >= MinimumContrastRatio {
+		return best
+	}
+
+	black, white := RGBColor("#000000"), RGBColor("#ffffff")
+	if contrastRatio(toRGB(black), b) >= contrastRatio(toRGB(white), b) {
+		return black
+	}
+	return white
+}
@@ -0,0 +1,86 @@
+package termenv
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestContrastRatio(t *testing.T) {
+	tt := []struct {
+		A, B     Color
+		Expected float64
+	}{
+		{RGBColor("#000000"), RGBColor("#ffffff"), 21},
+		{RGBColor("#ffffff"), RGBColor("#000000"), 21},
+		{RGBColor("#abcdef"), RGBColor("#abcdef"), 1},
+		{RGBColor("#777777"), RGBColor("#ffffff"), 4.48},
+		{ANSIBrightWhite, ANSIBlack, 21},
+	}
+
+	for _, test := range tt {
+		t.Run("", func(t *testing.T) {
+			if r := ContrastRatio(test.A, test.B); math.Abs(r-test.Expected) > 0.01 {
+				t.Errorf("Expected %.2f, got %.2f", test.Expected, r)
+			}
+		})
+	}
+}
+
+func TestAPCAContrast(t *testing.T) {
+	tt := []struct {
+		Text, Bg Color
+		Expected float64
+	}{
+		{RGBColor("#000000"), RGBColor("#ffffff"), 106.04},
+		{RGBColor("#ffffff"), RGBColor("#000000"), -107.88},
+		{RGBColor("#888888"), RGBColor("#ffffff"), 63.06},
+		{RGBColor("#ffffff"), RGBColor("#888888"), -68.54},
+		{RGBColor("#abcdef"), RGBColor("#abcdef"), 0},
+	}
+
+	for _, test := range tt {
+		t.Run("", func(t *testing.T) {
+			if lc := APCAContrast(test.Text, test.Bg); math.Abs(lc-test.Expected) > 0.01 {
+				t.Errorf("Expected %.2f, got %.2f", test.Expected, lc)
+			}
+		})
+	}
+}
+
+func TestReadableForeground(t *testing.T) {
+	tt := []struct {
+		Bg         Color
+		Candidates []Color
+		Expected   Color
+	}{
+		{RGBColor("#ffffff"), nil, RGBColor("#000000")},
+		{RGBColor("#000080"), nil, RGBColor("#ffffff")},
+		{RGBColor("#ffffff"), []Color{RGBColor("#ffff00"), RGBColor("#0000ff")}, RGBColor("#0000ff")},
+		{RGBColor("#ffffff"), []Color{RGBColor("#ffff00"), RGBColor("#00ffff")}, RGBColor("#000000")},
+		{RGBColor("#000000"), []Color{nil, ANSIBrightYellow}, ANSIBrightYellow},
+	}
+
+	for _, test := range tt {
+		t.Run("", func(t *testing.T) {
+			if c := ReadableForeground(test.Bg, test.Candidates...); c != test.Expected {
+				t.Errorf("Expected %v, got %v", test.Expected, c)
+			}
+		})
+	}
+}
+
+func TestOutputReadableForeground(t *testing.T) {
+	p := DefaultPalette()
+	p[ANSIBlack] = RGBColor("#ffffff")
+
+	// the terminal's background is used in place of NoColor
+	o := NewOutput(&bytes.Buffer{}, WithPalette(p))
+	o.bgColor = ANSIBlack
+	if c := o.ReadableForeground(NoColor{}); c != RGBColor("#000000") {
+		t.Errorf("Expected %v, got %v", RGBColor("#000000"), c)
+	}
+	if c := o.ReadableForeground(RGBColor("#000000")); c != RGBColor("#ffffff") {
+		t.Errorf("Expected %v, got %v", RGBColor("#ffffff"), c)
+	}
+}
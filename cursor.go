@@ -0,0 +1,53 @@
+package termenv
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// CursorPosition returns the current cursor position (1-based) by querying
+// the terminal.
+func (o *Output) CursorPosition() (row, col int, err error) {
+	replies, err := o.Query(context.Background(), CSI+"6n", func(r Reply) bool {
+		return r.Type == CSIReply && strings.HasSuffix(r.Payload, "R")
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(replies) == 0 {
+		return 0, 0, ErrStatusReport
+	}
+
+	return parseCursorPosition(replies[0].String())
+}
+
+// CursorPosition returns the current cursor position (1-based) by querying
+// the terminal.
+func CursorPosition() (row, col int, err error) {
+	return output.CursorPosition()
+}
+
+// parseCursorPosition parses a cursor position report: "\x1b[42;1R".
+func parseCursorPosition(s string) (row, col int, err error) {
+	if !strings.HasPrefix(s, CSI) || !strings.HasSuffix(s, "R") {
+		return 0, 0, ErrStatusReport
+	}
+
+	s = strings.TrimSuffix(strings.TrimPrefix(s, CSI), "R")
+	p := strings.Split(s, ";")
+	if len(p) != 2 { //nolint:mnd
+		return 0, 0, ErrStatusReport
+	}
+
+	row, err = strconv.Atoi(p[0])
+	if err != nil {
+		return 0, 0, ErrStatusReport
+	}
+	col, err = strconv.Atoi(p[1])
+	if err != nil {
+		return 0, 0, ErrStatusReport
+	}
+
+	return row, col, nil
+}
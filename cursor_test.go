@@ -0,0 +1,33 @@
+package termenv
+
+import "testing"
+
+func TestParseCursorPosition(t *testing.T) {
+	tests := []struct {
+		input    string
+		row, col int
+		valid    bool
+	}{
+		{"\x1b[42;1R", 42, 1, true},
+		{"\x1b[1;120R", 1, 120, true},
+		{"\x1b[42R", 0, 0, false},
+		{"\x1b[42;1", 0, 0, false},
+		{"\x1b]42;1R", 0, 0, false},
+		{"\x1b[a;bR", 0, 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run("", func(t *testing.T) {
+			row, col, err := parseCursorPosition(test.input)
+			if err != nil && test.valid {
+				t.Fatalf("unexpected error for input %q: %v", test.input, err)
+			}
+			if err == nil && !test.valid {
+				t.Fatalf("expected error for input %q not found", test.input)
+			}
+			if row != test.row || col != test.col {
+				t.Fatalf("wrong position returned, want %d;%d, got %d;%d", test.row, test.col, row, col)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package termenv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+var (
+	// ErrInvalidProfile gets returned when a profile name is invalid.
+	ErrInvalidProfile = errors.New("invalid profile")
+
+	// ErrOutputEncoding gets returned when encoding or decoding an Output.
+	ErrOutputEncoding = errors.New("outputs can't be encoded")
+)
+
+// MarshalText implements encoding.TextMarshaler, using the profile's name.
+func (p Profile) MarshalText() ([]byte, error) {
+	switch p {
+	case Ascii, ANSI, ANSI256, TrueColor:
+		return []byte(p.Name()), nil
+	}
+	return nil, fmt.Errorf("%w: %d", ErrInvalidProfile, p)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Profile names are
+// case-insensitive.
+func (p *Profile) UnmarshalText(text []byte) error {
+	for _, v := range []Profile{Ascii, ANSI, ANSI256, TrueColor} {
+		if strings.EqualFold(v.Name(), string(text)) {
+			*p = v
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrInvalidProfile, text)
+}
+
+// MarshalText always returns ErrOutputEncoding. It keeps Output from
+// inheriting the method of its Profile, which would encode an Output as the
+// name of its profile. Encode o.Profile instead.
+func (o Output) MarshalText() ([]byte, error) {
+	return nil, ErrOutputEncoding
+}
+
+// UnmarshalText always returns ErrOutputEncoding. It keeps Output from
+// inheriting the method of its Profile, which would only overwrite the
+// profile of o. Decode a Profile and pass it to WithProfile instead.
+func (o *Output) UnmarshalText([]byte) error {
+	return ErrOutputEncoding
+}
+
+// MarshalText implements encoding.TextMarshaler. NoColor is encoded as an
+// empty string.
+func (c NoColor) MarshalText() ([]byte, error) {
+	return []byte{}, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Only empty strings are
+// valid.
+func (c *NoColor) UnmarshalText(text []byte) error {
+	if len(text) > 0 {
+		return fmt.Errorf("%w: %q", ErrInvalidColor, text)
+	}
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding the color as its
+// index.
+func (c ANSIColor) MarshalText() ([]byte, error) {
+	return []byte(strconv.Itoa(int(c))), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Both color indices
+// (0-15) and names of ANSI colors, such as "red", are valid.
+func (c *ANSIColor) UnmarshalText(text []byte) error {
+	if v, ok := ansiColorByName(string(text)); ok {
+		*c = v
+		return nil
+	}
+
+	i, err := parseColorIndex(text, len(ansiNames))
+	if err != nil {
+		return err
+	}
+	*c = ANSIColor(i)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, encoding the color as a number.
+func (c ANSIColor) MarshalJSON() ([]byte, error) {
+	return c.MarshalText()
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Both numbers and strings are
+// valid.
+func (c *ANSIColor) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+
+	text, err := unquoteJSON(b)
+	if err != nil {
+		return err
+	}
+	return c.UnmarshalText(text)
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding the color as its
+// index.
+func (c ANSI256Color) MarshalText() ([]byte, error) {
+	return []byte(strconv.Itoa(int(c))), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Valid inputs are color
+// indices (0-255).
+func (c *ANSI256Color) UnmarshalText(text []byte) error {
+	i, err := parseColorIndex(text, len(ansiHex))
+	if err != nil {
+		return err
+	}
+	*c = ANSI256Color(i)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, encoding the color as a number.
+func (c ANSI256Color) MarshalJSON() ([]byte, error) {
+	return c.MarshalText()
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Both numbers and strings are
+// valid.
+func (c *ANSI256Color) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+
+	text, err := unquoteJSON(b)
+	if err != nil {
+		return err
+	}
+	return c.UnmarshalText(text)
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding the color as a hex
+// string.
+func (c RGBColor) MarshalText() ([]byte, error) {
+	return []byte(c), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Valid inputs are hex
+// colors, e.g. "#abcdef".
+func (c *RGBColor) UnmarshalText(text []byte) error {
+	if _, err := colorful.Hex(string(text)); err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidColor, text)
+	}
+	*c = RGBColor(text)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding the color as a hex
+// string.
+func (c RGB) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Valid inputs are hex
+// colors, e.g. "#abcdef".
+func (c *RGB) UnmarshalText(text []byte) error {
+	v, err := ParseRGB(string(text))
+	if err != nil {
+		return err
+	}
+	*c = v
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding the style as its
+// spec. The text the style gets applied to is not encoded. Styles with
+// adaptive or complete colors can't be encoded, as specs can't express them.
+func (t Style) MarshalText() ([]byte, error) {
+	for _, c := range []Color{t.fg, t.bg, t.ul} {
+		switch c.(type) {
+		case AdaptiveColor, CompleteColor:
+			return nil, fmt.Errorf("%w: %T can't be encoded in a style spec", ErrInvalidColor, c)
+		}
+	}
+	return []byte(t.Spec()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing a style spec as
+// described in ParseStyle. Colors get converted to the profile of t, and the
+// Output of t if it was created with Output.String, while the text of t is
+// kept.
+func (t *Style) UnmarshalText(text []byte) error {
+	s, err := parseStyle(Style{profile: t.profile, output: t.output, string: t.string}, string(text))
+	if err != nil {
+		return err
+	}
+
+	*t = s
+	return nil
+}
+
+// parseColorIndex parses a color index lower than n.
+func parseColorIndex(text []byte, n int) (int, error) {
+	i, err := strconv.Atoi(string(text))
+	if err != nil || i < 0 || i >= n {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidColor, text)
+	}
+	return i, nil
+}
+
+// unquoteJSON returns the contents of a JSON string, or b itself if it is not
+// a string.
+func unquoteJSON(b []byte) ([]byte, error) {
+	if len(b) == 0 || b[0] != '"' {
+		return b, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	return []byte(s), nil
+}
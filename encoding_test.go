@@ -0,0 +1,141 @@
+package termenv
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+)
+
+type testTheme struct {
+	Profile Profile      `json:"profile"`
+	Accent  ANSIColor    `json:"accent"`
+	Muted   ANSI256Color `json:"muted"`
+	Brand   RGBColor     `json:"brand"`
+	Title   Style        `json:"title"`
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	theme := testTheme{
+		Profile: ANSI256,
+		Accent:  ANSIBrightRed,
+		Muted:   ANSI256Color(236),
+		Brand:   RGBColor("#ff8800"),
+		Title:   TrueColor.String().Bold().Foreground(RGBColor("#ff8800")).Background(ANSI256Color(236)),
+	}
+
+	b, err := json.Marshal(theme)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exp := `{"profile":"ANSI256","accent":9,"muted":236,"brand":"#ff8800","title":"bold #ff8800 on 236"}`
+	if string(b) != exp {
+		t.Errorf("Expected %s, got %s", exp, b)
+	}
+
+	var r testTheme
+	r.Title = TrueColor.String()
+	if err := json.Unmarshal(b, &r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r != theme {
+		t.Errorf("Expected %+v, got %+v", theme, r)
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	var theme testTheme
+	in := `{"profile":"truecolor","accent":"brightblue","muted":"42","brand":"#abcdef","title":"italic red"}`
+	if err := json.Unmarshal([]byte(in), &theme); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exp := testTheme{
+		Profile: TrueColor,
+		Accent:  ANSIBrightBlue,
+		Muted:   ANSI256Color(42),
+		Brand:   RGBColor("#abcdef"),
+		Title:   TrueColor.String().Italic().Foreground(ANSIRed),
+	}
+	if theme != exp {
+		t.Errorf("Expected %+v, got %+v", exp, theme)
+	}
+}
+
+func TestUnmarshalTextErrors(t *testing.T) {
+	var p Profile
+	var a ANSIColor
+	var a256 ANSI256Color
+	var rgb RGBColor
+	var n NoColor
+	var s Style
+
+	tt := []struct {
+		Err error
+		Fn  func() error
+	}{
+		{ErrInvalidProfile, func() error { return p.UnmarshalText([]byte("CGA")) }},
+		{ErrInvalidColor, func() error { return a.UnmarshalText([]byte("16")) }},
+		{ErrInvalidColor, func() error { return a.UnmarshalText([]byte("pink")) }},
+		{ErrInvalidColor, func() error { return a256.UnmarshalText([]byte("256")) }},
+		{ErrInvalidColor, func() error { return a256.UnmarshalText([]byte("-1")) }},
+		{ErrInvalidColor, func() error { return rgb.UnmarshalText([]byte("#ggg")) }},
+		{ErrInvalidColor, func() error { return n.UnmarshalText([]byte("red")) }},
+		{ErrInvalidStyle, func() error { return s.UnmarshalText([]byte("bold frobnicate")) }},
+	}
+
+	for _, test := range tt {
+		t.Run("", func(t *testing.T) {
+			if err := test.Fn(); !errors.Is(err, test.Err) {
+				t.Errorf("Expected error %v, got %v", test.Err, err)
+			}
+		})
+	}
+
+	if _, err := Profile(42).MarshalText(); !errors.Is(err, ErrInvalidProfile) {
+		t.Errorf("Expected error %v, got %v", ErrInvalidProfile, err)
+	}
+}
+
+func TestOutputEncoding(t *testing.T) {
+	o := NewOutput(io.Discard, WithProfile(ANSI))
+
+	if _, err := json.Marshal(o); !errors.Is(err, ErrOutputEncoding) {
+		t.Errorf("Expected %v, got %v", ErrOutputEncoding, err)
+	}
+	if err := json.Unmarshal([]byte(`"TrueColor"`), o); !errors.Is(err, ErrOutputEncoding) {
+		t.Errorf("Expected %v, got %v", ErrOutputEncoding, err)
+	}
+	if o.Profile != ANSI {
+		t.Errorf("Expected %s, got %s", ANSI.Name(), o.Profile.Name())
+	}
+
+	// the profile itself can still be encoded
+	b, err := json.Marshal(o.Profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `"ANSI"` {
+		t.Errorf("Expected %q, got %q", `"ANSI"`, b)
+	}
+}
+
+func TestMarshalStyleAdaptiveColors(t *testing.T) {
+	tt := []Style{
+		TrueColor.String().Foreground(AdaptiveColor{Light: ANSIBlack, Dark: ANSIWhite}),
+		TrueColor.String().Background(CompleteColor{TrueColor: RGBColor("#ff0000"), ANSI: ANSIBlue}),
+		TrueColor.String().UnderlineColor(AdaptiveColor{Light: ANSIBlack, Dark: ANSIWhite}),
+	}
+
+	for _, s := range tt {
+		t.Run("", func(t *testing.T) {
+			if _, err := s.MarshalText(); !errors.Is(err, ErrInvalidColor) {
+				t.Errorf("Expected %v, got %v", ErrInvalidColor, err)
+			}
+			if _, err := json.Marshal(s); !errors.Is(err, ErrInvalidColor) {
+				t.Errorf("Expected %v, got %v", ErrInvalidColor, err)
+			}
+		})
+	}
+}
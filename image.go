@@ -0,0 +1,214 @@
+package termenv
+
+import (
+	"image"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// Dithering is the method used to dither images when rendering them to a
+// Profile with a limited number of colors.
+type Dithering int
+
+// Dithering methods.
+const (
+	// NoDithering converts each pixel to its closest color.
+	NoDithering Dithering = iota
+	// FloydSteinbergDithering diffuses the conversion error of each pixel
+	// to its neighbors.
+	FloydSteinbergDithering
+	// OrderedDithering offsets pixels using an 8x8 Bayer matrix. Unlike
+	// error diffusion, pixels don't affect each other, so animations and
+	// scrolling gradients don't flicker.
+	OrderedDithering
+)
+
+// Half block characters used to render two pixels per cell.
+const (
+	upperHalfBlock = "▀"
+	lowerHalfBlock = "▄"
+)
+
+// bayerMatrix is the 8x8 threshold map for ordered dithering.
+var bayerMatrix = [8][8]float64{
+	{0, 32, 8, 40, 2, 34, 10, 42},
+	{48, 16, 56, 24, 50, 18, 58, 26},
+	{12, 44, 4, 36, 14, 46, 6, 38},
+	{60, 28, 52, 20, 62, 30, 54, 22},
+	{3, 35, 11, 43, 1, 33, 9, 41},
+	{51, 19, 59, 27, 49, 17, 57, 25},
+	{15, 47, 7, 39, 13, 45, 5, 37},
+	{63, 31, 55, 23, 61, 29, 53, 21},
+}
+
+// RenderImage renders an image using half block characters, with each cell
+// showing two vertically adjacent pixels as its foreground and background
+// colors. Each row of cells ends with a reset, and rows are separated by
+// newlines.
+//
+// Colors get converted to the Output's Profile, taking its palette and
+// quantizer into account, and dithered using the given method. Pixels that
+// are more than half transparent are left blank. The result only depends on
+// the image, the Output's settings and the dithering method, so it is
+// deterministic. On the Ascii profile, all pixels are left blank.
+func (o Output) RenderImage(img image.Image, d Dithering) string {
+	pixels := o.imageColors(img, d)
+
+	var sb strings.Builder
+	for y := 0; y < len(pixels); y += 2 {
+		if y > 0 {
+			sb.WriteString("\n")
+		}
+
+		var fg, bg Color = NoColor{}, NoColor{}
+		for x := range pixels[y] {
+			var bottom Color = NoColor{}
+			if y+1 < len(pixels) {
+				bottom = pixels[y+1][x]
+			}
+			cfg, cbg, s := halfBlockCell(pixels[y][x], bottom)
+
+			writeColorChange(&sb, fg, bg, cfg, cbg)
+			fg, bg = cfg, cbg
+			sb.WriteString(s)
+		}
+
+		if fg != (NoColor{}) || bg != (NoColor{}) {
+			sb.WriteString(CSI + ResetSeq + "m")
+		}
+	}
+
+	return sb.String()
+}
+
+// halfBlockCell returns the foreground and background colors and the
+// character of a cell showing the pixels top and bottom.
+func halfBlockCell(top, bottom Color) (Color, Color, string) {
+	switch {
+	case top == NoColor{} && bottom == NoColor{}:
+		return NoColor{}, NoColor{}, " "
+	case top == NoColor{}:
+		return bottom, NoColor{}, lowerHalfBlock
+	default:
+		return top, bottom, upperHalfBlock
+	}
+}
+
+// writeColorChange writes the sequence switching from the colors fg and bg
+// to nfg and nbg. Unsetting a color resets the style.
+func writeColorChange(sb *strings.Builder, fg, bg, nfg, nbg Color) {
+	if fg == nfg && bg == nbg {
+		return
+	}
+
+	var seq []string
+	if (fg != nfg && nfg == NoColor{}) || (bg != nbg && nbg == NoColor{}) {
+		seq = append(seq, ResetSeq)
+		fg, bg = NoColor{}, NoColor{}
+	}
+	if fg != nfg {
+		seq = append(seq, nfg.Sequence(false))
+	}
+	if bg != nbg {
+		seq = append(seq, nbg.Sequence(true))
+	}
+
+	var seqs []string
+	for _, s := range seq {
+		if s != "" {
+			seqs = append(seqs, s)
+		}
+	}
+	if len(seqs) > 0 {
+		sb.WriteString(CSI + strings.Join(seqs, ";") + "m")
+	}
+}
+
+// imageColors converts the pixels of an image to the Output's Profile.
+// Transparent pixels become NoColor.
+func (o Output) imageColors(img image.Image, d Dithering) [][]Color {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	// TrueColor doesn't need dithering, and Ascii can't show any colors
+	if o.Profile == TrueColor || o.Profile == Ascii {
+		d = NoDithering
+	}
+
+	pixels := make([][]Color, h)
+	errs := make([][]colorful.Color, h)
+	for y := range pixels {
+		pixels[y] = make([]Color, w)
+		errs[y] = make([]colorful.Color, w)
+	}
+
+	spread := o.ditherSpread()
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			px := img.At(b.Min.X+x, b.Min.Y+y)
+			c, ok := colorful.MakeColor(px)
+			if _, _, _, a := px.RGBA(); !ok || a < 0x8000 {
+				pixels[y][x] = NoColor{}
+				continue
+			}
+
+			switch d {
+			case FloydSteinbergDithering:
+				e := errs[y][x]
+				c = colorful.Color{R: c.R + e.R, G: c.G + e.G, B: c.B + e.B}.Clamped()
+			case OrderedDithering:
+				t := (bayerMatrix[y%8][x%8]+0.5)/64 - 0.5 //nolint:mnd
+				c = colorful.Color{R: c.R + t*spread, G: c.G + t*spread, B: c.B + t*spread}.Clamped()
+			}
+
+			if d == NoDithering {
+				r, g, b := c.RGB255()
+				pixels[y][x] = o.Convert(RGB{R: r, G: g, B: b})
+				continue
+			}
+
+			pc := o.quantize(c)
+			pixels[y][x] = pc
+
+			if d == FloydSteinbergDithering {
+				q := o.convertToRGB(pc)
+				e := colorful.Color{R: c.R - q.R, G: c.G - q.G, B: c.B - q.B}
+				diffuseError(errs, x+1, y, e, 7.0/16)   //nolint:mnd
+				diffuseError(errs, x-1, y+1, e, 3.0/16) //nolint:mnd
+				diffuseError(errs, x, y+1, e, 5.0/16)   //nolint:mnd
+				diffuseError(errs, x+1, y+1, e, 1.0/16) //nolint:mnd
+			}
+		}
+	}
+
+	return pixels
+}
+
+// quantize converts c to the closest color of the Output's Profile. Unlike
+// Convert, colors get converted directly to the 16 ANSI colors on the ANSI
+// profile, so the conversion error can be diffused.
+func (o Output) quantize(c colorful.Color) Color {
+	if o.Profile == ANSI {
+		return toANSIColor(c, o.palette, o.quantizer)
+	}
+	return toANSI256Color(c, o.quantizer)
+}
+
+// ditherSpread returns the strength of ordered dithering, roughly matching
+// the distance between neighboring colors of the Output's Profile.
+func (o Output) ditherSpread() float64 {
+	if o.Profile == ANSI {
+		return 1.0 / 2 //nolint:mnd
+	}
+	return 1.0 / 6 //nolint:mnd
+}
+
+func diffuseError(errs [][]colorful.Color, x, y int, e colorful.Color, f float64) {
+	if y >= len(errs) || x < 0 || x >= len(errs[y]) {
+		return
+	}
+	errs[y][x].R += e.R * f
+	errs[y][x].G += e.G * f
+	errs[y][x].B += e.B * f
+}
@@ -0,0 +1,122 @@
+package termenv
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// testImage returns a gradient from red to blue, getting darker towards the
+// bottom, with a transparent top-left pixel and an odd number of rows.
+func testImage() image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, 16, 7))
+	for y := 0; y < 7; y++ {
+		for x := 0; x < 16; x++ {
+			c := colorful.Color{R: 1, G: 0, B: 0}.BlendOkLab(colorful.Color{R: 0, G: 0, B: 1}, float64(x)/15)
+			c = c.BlendRgb(colorful.Color{}, float64(y)/10).Clamped()
+			r, g, b := c.RGB255()
+			img.SetNRGBA(x, y, color.NRGBA{r, g, b, 0xff})
+		}
+	}
+	img.SetNRGBA(0, 0, color.NRGBA{})
+	return img
+}
+
+func TestRenderImage(t *testing.T) {
+	tt := []struct {
+		name      string
+		profile   Profile
+		dithering Dithering
+	}{
+		{"truecolor", TrueColor, NoDithering},
+		{"ansi256", ANSI256, NoDithering},
+		{"ansi256_floydsteinberg", ANSI256, FloydSteinbergDithering},
+		{"ansi256_ordered", ANSI256, OrderedDithering},
+		{"ansi", ANSI, NoDithering},
+		{"ansi_floydsteinberg", ANSI, FloydSteinbergDithering},
+		{"ansi_ordered", ANSI, OrderedDithering},
+		{"ascii", Ascii, FloydSteinbergDithering},
+	}
+
+	img := testImage()
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			o := NewOutput(io.Discard, WithProfile(test.profile))
+			actual := o.RenderImage(img, test.dithering)
+
+			filename := fmt.Sprintf("./testdata/image_%s.txt", test.name)
+			expected, err := os.ReadFile(filename)
+			if err != nil {
+				t.Fatalf("unexpected error reading golden file %q: %v", filename, err)
+			}
+			if string(expected) != actual {
+				t.Fatalf("image output does not match golden file.\n--- Expected ---\n%s\n--- Actual ---\n%s\n", string(expected), actual)
+			}
+
+			// rendering must be deterministic
+			if again := o.RenderImage(img, test.dithering); again != actual {
+				t.Errorf("Expected identical output when rendering twice")
+			}
+		})
+	}
+}
+
+func TestRenderImageCells(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	img.SetNRGBA(0, 0, color.NRGBA{0xff, 0, 0, 0xff})
+	img.SetNRGBA(0, 1, color.NRGBA{0, 0, 0xff, 0xff})
+	img.SetNRGBA(1, 1, color.NRGBA{0, 0xff, 0, 0xff})
+
+	o := NewOutput(io.Discard, WithProfile(TrueColor))
+	actual := o.RenderImage(img, NoDithering)
+	expected := "\x1b[38;2;255;0;0;48;2;0;0;255m▀\x1b[0;38;2;0;255;0m▄\x1b[0m "
+	if actual != expected {
+		t.Errorf("Expected %q, got %q", expected, actual)
+	}
+}
+
+func TestRenderImageDithering(t *testing.T) {
+	// a gray that sits between two of the 16 ANSI colors
+	gray := colorful.Color{R: 0.6, G: 0.6, B: 0.6}
+	img := image.NewNRGBA(image.Rect(0, 0, 16, 16))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = 0x99, 0x99, 0x99, 0xff
+	}
+
+	o := NewOutput(io.Discard, WithProfile(ANSI))
+	for _, d := range []Dithering{NoDithering, FloydSteinbergDithering, OrderedDithering} {
+		pixels := o.imageColors(img, d)
+
+		var r, g, b float64
+		colors := map[Color]bool{}
+		for _, row := range pixels {
+			for _, c := range row {
+				colors[c] = true
+				rgb := o.convertToRGB(c)
+				r += rgb.R
+				g += rgb.G
+				b += rgb.B
+			}
+		}
+		n := float64(len(pixels) * len(pixels[0]))
+		avg := colorful.Color{R: r / n, G: g / n, B: b / n}
+
+		if d == NoDithering {
+			if len(colors) != 1 {
+				t.Errorf("Expected a single color without dithering, got %d", len(colors))
+			}
+			continue
+		}
+		if len(colors) < 2 {
+			t.Errorf("Dithering %d: expected several colors, got %d", d, len(colors))
+		}
+		if dist := avg.DistanceRgb(gray); dist > 0.05 {
+			t.Errorf("Dithering %d: expected average color close to %s, got %s", d, gray.Hex(), avg.Hex())
+		}
+	}
+}
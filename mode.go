@@ -0,0 +1,100 @@
+package termenv
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DEC private modes.
+const (
+	CursorVisibilityMode   = 25
+	MouseMode              = 1000
+	MouseCellMotionMode    = 1002
+	MouseAllMotionMode     = 1003
+	FocusEventsMode        = 1004
+	MouseExtendedMode      = 1006
+	AltScreenMode          = 1049
+	BracketedPasteMode     = 2004
+	SynchronizedOutputMode = 2026
+	GraphemeClusteringMode = 2027
+	ColorSchemeUpdatesMode = 2031
+)
+
+// ModeState is the state of a terminal mode, as reported by the terminal.
+type ModeState int
+
+// Mode states.
+const (
+	// ModeNotRecognized means the terminal doesn't know the mode.
+	ModeNotRecognized ModeState = iota
+	// ModeSet means the mode is enabled.
+	ModeSet
+	// ModeReset means the mode is disabled.
+	ModeReset
+	// ModePermanentlySet means the mode is enabled and can't be disabled.
+	ModePermanentlySet
+	// ModePermanentlyReset means the mode is disabled and can't be enabled.
+	ModePermanentlyReset
+)
+
+// String returns the mode state as a string.
+func (m ModeState) String() string {
+	switch m {
+	case ModeNotRecognized:
+		return "not recognized"
+	case ModeSet:
+		return "set"
+	case ModeReset:
+		return "reset"
+	case ModePermanentlySet:
+		return "permanently set"
+	case ModePermanentlyReset:
+		return "permanently reset"
+	}
+	return "unknown"
+}
+
+// IsRecognized reports whether the terminal knows the mode.
+func (m ModeState) IsRecognized() bool {
+	return m != ModeNotRecognized
+}
+
+// IsSet reports whether the mode is enabled.
+func (m ModeState) IsSet() bool {
+	return m == ModeSet || m == ModePermanentlySet
+}
+
+// QueryMode asks the terminal for the state of a DEC private mode, e.g.
+// SynchronizedOutputMode, using DECRQM. This lets you check whether a mode is
+// supported before enabling it.
+func (o *Output) QueryMode(mode int) (ModeState, error) {
+	prefix := fmt.Sprintf("?%d;", mode)
+	replies, err := o.Query(context.Background(), fmt.Sprintf(CSI+"?%d$p", mode), func(r Reply) bool {
+		return r.Type == CSIReply && strings.HasPrefix(r.Payload, prefix) && strings.HasSuffix(r.Payload, "$y")
+	})
+	if err != nil {
+		return ModeNotRecognized, err
+	}
+
+	// the terminal does not support DECRQM
+	if len(replies) == 0 {
+		return ModeNotRecognized, ErrStatusReport
+	}
+
+	return parseModeReport(replies[0].Payload)
+}
+
+// parseModeReport parses a DECRPM reply: "?2026;2$y".
+func parseModeReport(s string) (ModeState, error) {
+	p := parseParams(strings.TrimSuffix(strings.TrimPrefix(s, "?"), "$y"))
+	if len(p) != 2 { //nolint:mnd
+		return ModeNotRecognized, ErrStatusReport
+	}
+
+	m := ModeState(p[1])
+	if m < ModeNotRecognized || m > ModePermanentlyReset {
+		return ModeNotRecognized, ErrStatusReport
+	}
+	return m, nil
+}
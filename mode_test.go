@@ -0,0 +1,43 @@
+package termenv
+
+import "testing"
+
+func TestParseModeReport(t *testing.T) {
+	tests := []struct {
+		input string
+		state ModeState
+		valid bool
+	}{
+		{"?2026;0$y", ModeNotRecognized, true},
+		{"?2026;1$y", ModeSet, true},
+		{"?2026;2$y", ModeReset, true},
+		{"?2026;3$y", ModePermanentlySet, true},
+		{"?2026;4$y", ModePermanentlyReset, true},
+		{"?2026;5$y", ModeNotRecognized, false},
+		{"?2026$y", ModeNotRecognized, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			m, err := parseModeReport(test.input)
+			if err != nil && test.valid {
+				t.Fatalf("unexpected error for input %q: %v", test.input, err)
+			}
+			if err == nil && !test.valid {
+				t.Fatalf("expected error for input %q not found", test.input)
+			}
+			if m != test.state {
+				t.Errorf("Expected %s, got %s", test.state, m)
+			}
+		})
+	}
+}
+
+func TestModeState(t *testing.T) {
+	if ModeNotRecognized.IsRecognized() {
+		t.Errorf("Expected mode not to be recognized")
+	}
+	if !ModePermanentlySet.IsSet() || ModePermanentlyReset.IsSet() {
+		t.Errorf("Unexpected IsSet result")
+	}
+}
@@ -1,9 +1,12 @@
 package termenv
 
 import (
+	"image/color"
 	"io"
 	"os"
 	"sync"
+
+	"github.com/lucasb-eyer/go-colorful"
 )
 
 // output is the default global output.
@@ -33,6 +36,12 @@ type Output struct {
 	fgColor   Color
 	bgSync    *sync.Once
 	bgColor   Color
+	csSync    *sync.Once
+	scheme    ColorScheme
+	palette   *Palette
+	quantizer Quantizer
+	colors    *colorCache
+	termcap   bool
 }
 
 // Environ is an interface for getting environment variables.
@@ -71,6 +80,7 @@ func NewOutput(w io.Writer, opts ...OutputOption) *Output {
 		fgColor: NoColor{},
 		bgSync:  &sync.Once{},
 		bgColor: NoColor{},
+		csSync:  &sync.Once{},
 	}
 
 	if o.w == nil {
@@ -82,6 +92,9 @@ func NewOutput(w io.Writer, opts ...OutputOption) *Output {
 	if o.Profile < 0 {
 		o.Profile = o.EnvColorProfile()
 	}
+	if o.palette != nil || o.quantizer != nil {
+		o.colors = newColorCache()
+	}
 
 	return o
 }
@@ -109,6 +122,7 @@ func WithColorCache(v bool) OutputOption {
 		// cache the values now
 		_ = o.ForegroundColor()
 		_ = o.BackgroundColor()
+		_ = o.colorScheme()
 	}
 }
 
@@ -133,6 +147,36 @@ func WithUnsafe() OutputOption {
 	}
 }
 
+// WithPalette returns a new OutputOption that converts colors to the closest
+// color of the given palette, instead of xterm's default ANSI colors, when
+// downsampling to the ANSI profile. Use Output.Palette to query the
+// terminal's actual palette.
+func WithPalette(p Palette) OutputOption {
+	return func(o *Output) {
+		o.palette = &p
+	}
+}
+
+// WithQuantizer returns a new OutputOption that picks the closest colors
+// with the given Quantizer when downsampling colors, e.g. OkLabQuantizer{} or
+// DirectQuantizer{CIEDE2000Quantizer{}}.
+func WithQuantizer(q Quantizer) OutputOption {
+	return func(o *Output) {
+		o.quantizer = q
+	}
+}
+
+// WithTermcapQuery returns a new OutputOption that lets ColorProfile ask the
+// terminal for its RGB and Tc capabilities, and upgrade the profile to
+// TrueColor if the terminal confirms either of them. This is useful when
+// COLORTERM is not set, e.g. on SSH sessions. It's only supported on Unix
+// systems.
+func WithTermcapQuery(v bool) OutputOption {
+	return func(o *Output) {
+		o.termcap = v
+	}
+}
+
 // ForegroundColor returns the terminal's default foreground color.
 func (o *Output) ForegroundColor() Color {
 	f := func() {
@@ -171,13 +215,100 @@ func (o *Output) BackgroundColor() Color {
 	return o.bgColor
 }
 
-// HasDarkBackground returns whether terminal uses a dark-ish background.
+// colorScheme returns the color scheme reported by the terminal, or
+// UnknownColorScheme if the terminal doesn't report it. To avoid an additional
+// round-trip on every call, the terminal only gets queried once, and only if
+// colors are cached.
+func (o *Output) colorScheme() ColorScheme {
+	if !o.cache {
+		return UnknownColorScheme
+	}
+
+	o.csSync.Do(func() {
+		if !o.isTTY() {
+			return
+		}
+
+		o.scheme, _ = o.ColorScheme()
+	})
+
+	return o.scheme
+}
+
+// HasDarkBackground returns whether terminal uses a dark-ish background. With
+// WithColorCache, the color scheme reported by the terminal is preferred, if
+// available. Otherwise the luminance of the background color is used.
 func (o *Output) HasDarkBackground() bool {
-	c := ConvertToRGB(o.BackgroundColor())
+	switch o.colorScheme() {
+	case DarkColorScheme:
+		return true
+	case LightColorScheme:
+		return false
+	}
+
+	c := o.convertToRGB(o.BackgroundColor())
 	_, _, l := c.Hsl()
 	return l < 0.5 //nolint:mnd
 }
 
+// Convert transforms a given Color to a Color supported by the Output's
+// Profile, taking its palette and quantizer into account. Adaptive colors get resolved
+// against the Output's background color.
+func (o Output) Convert(c Color) Color {
+	if v, ok := c.(AdaptiveColor); ok {
+		c = v.resolve(o.HasDarkBackground())
+	}
+	return o.colorCache().convert(o.Profile, c, o.palette, o.quantizer)
+}
+
+// String returns a new Style for the Output's Profile. Colors used in the
+// Style get converted with the Output's palette and quantizer. With
+// WithColorCache, adaptive colors get resolved against the Output's cached
+// background color, otherwise they use their Dark color.
+func (o *Output) String(s ...string) Style {
+	t := o.Profile.String(s...)
+	t.output = o
+	return t
+}
+
+// Color creates a Color from a string. Valid inputs are all colors understood
+// by ParseColor. It returns nil for invalid inputs.
+func (o Output) Color(s string) Color {
+	c := parseColor(s)
+	if c == nil {
+		return nil
+	}
+
+	return o.Convert(c)
+}
+
+// FromColor creates a Color from a color.Color.
+func (o Output) FromColor(c color.Color) Color {
+	col, _ := colorful.MakeColor(c)
+	return o.Color(col.Hex())
+}
+
+// colorCache returns the cache for color conversions of the Output. Outputs
+// using the default palette and quantizer share a cache.
+func (o Output) colorCache() *colorCache {
+	if o.colors == nil {
+		return defaultColorCache
+	}
+	return o.colors
+}
+
+// convertToRGB converts a Color to a colorful.Color, resolving ANSI colors
+// using the Output's palette.
+func (o Output) convertToRGB(c Color) colorful.Color {
+	if v, ok := c.(AdaptiveColor); ok {
+		c = v.resolve(o.HasDarkBackground())
+	}
+	if v, ok := c.(ANSIColor); ok && o.palette != nil && v >= 0 && int(v) < len(o.palette) {
+		c = o.palette[v]
+	}
+	return ConvertToRGB(c)
+}
+
 // TTY returns the terminal's file descriptor. This may be nil if the output is
 // not a terminal.
 //
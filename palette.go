@@ -0,0 +1,119 @@
+package termenv
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// Palette holds the RGB values of the 16 ANSI colors.
+type Palette [16]RGBColor
+
+// DefaultPalette returns xterm's default values for the 16 ANSI colors.
+func DefaultPalette() Palette {
+	var p Palette
+	for i := range p {
+		p[i] = RGBColor(ansiHex[i])
+	}
+	return p
+}
+
+// Palette queries the terminal for the RGB values of its 16 ANSI colors,
+// using OSC 4. Colors the terminal did not report keep their default value.
+//
+//nolint:mnd
+func (o *Output) Palette() (Palette, error) {
+	p := DefaultPalette()
+	if !o.canQueryOSC() {
+		return p, ErrStatusReport
+	}
+
+	// query all colors in a single round-trip
+	var req strings.Builder
+	for i := range p {
+		fmt.Fprintf(&req, OSC+"4;%d;?"+ST, i)
+	}
+
+	replies, err := o.Query(context.Background(), req.String(), func(r Reply) bool {
+		return r.Type == OSCReply && strings.HasPrefix(r.Payload, "4;")
+	})
+	if err != nil {
+		return p, err
+	}
+
+	var n int
+	for _, r := range replies {
+		// "4;1;rgb:cdcd/0000/0000"
+		v := strings.SplitN(r.Payload, ";", 3)
+		if len(v) != 3 {
+			continue
+		}
+		i, err := strconv.Atoi(v[1])
+		if err != nil || i < 0 || i >= len(p) {
+			continue
+		}
+		c, err := xParseColor(v[2])
+		if err != nil {
+			continue
+		}
+
+		p[i] = c
+		n++
+	}
+
+	// the terminal does not support querying its palette
+	if n == 0 {
+		return p, ErrStatusReport
+	}
+
+	return p, nil
+}
+
+// colors returns the RGB values of the palette's colors.
+func (p Palette) colors() []colorful.Color {
+	c := make([]colorful.Color, len(p))
+	for i, pc := range p {
+		c[i], _ = colorful.Hex(string(pc))
+	}
+	return c
+}
+
+// xParseColor parses a color in the "rgb:r/g/b" notation used by X11, where
+// each component consists of one to four hex digits.
+func xParseColor(s string) (RGBColor, error) {
+	if !strings.HasPrefix(s, "rgb:") {
+		return "", ErrInvalidColor
+	}
+
+	h := strings.Split(strings.TrimPrefix(s, "rgb:"), "/")
+	if len(h) != 3 { //nolint:mnd
+		return "", ErrInvalidColor
+	}
+
+	var rgb [3]uint64
+	for i, v := range h {
+		if len(v) == 0 || len(v) > 4 {
+			return "", ErrInvalidColor
+		}
+		c, err := strconv.ParseUint(v, 16, 16)
+		if err != nil {
+			return "", ErrInvalidColor
+		}
+
+		// scale to 8 bits
+		switch len(v) {
+		case 1:
+			c *= 0x11
+		case 3: //nolint:mnd
+			c >>= 4
+		case 4: //nolint:mnd
+			c >>= 8
+		}
+		rgb[i] = c
+	}
+
+	return RGBColor(fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2])), nil
+}
@@ -0,0 +1,75 @@
+package termenv
+
+import (
+	"io"
+	"testing"
+)
+
+func TestXParseColor(t *testing.T) {
+	tests := []struct {
+		input string
+		color RGBColor
+		valid bool
+	}{
+		{"rgb:ffff/8080/0000", RGBColor("#ff8000"), true},
+		{"rgb:fff/808/000", RGBColor("#ff8000"), true},
+		{"rgb:ff/80/00", RGBColor("#ff8000"), true},
+		{"rgb:f/8/0", RGBColor("#ff8800"), true},
+		{"rgb:ffff/8080", "", false},
+		{"rgb:fffff/8080/0000", "", false},
+		{"rgb:gggg/8080/0000", "", false},
+		{"rgba:ffff/8080/0000", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			c, err := xParseColor(test.input)
+			if err != nil && test.valid {
+				t.Fatalf("unexpected error for input %q: %v", test.input, err)
+			}
+			if err == nil && !test.valid {
+				t.Fatalf("expected error for input %q not found", test.input)
+			}
+			if c != test.color {
+				t.Fatalf("wrong color returned, want %v, got %v", test.color, c)
+			}
+		})
+	}
+}
+
+func TestPaletteConvert(t *testing.T) {
+	// a palette where the "red" slot holds an orange tone
+	p := DefaultPalette()
+	p[ANSIRed] = RGBColor("#ff8700")
+
+	o := NewOutput(io.Discard, WithProfile(ANSI), WithPalette(p))
+	if c := o.Color("#ff8800"); c != ANSIRed {
+		t.Errorf("Expected %v, got %v", ANSIRed, c)
+	}
+	if c := o.Color("208"); c != ANSIRed {
+		t.Errorf("Expected %v, got %v", ANSIRed, c)
+	}
+
+	// without a palette, xterm's default colors are used
+	o = NewOutput(io.Discard, WithProfile(ANSI))
+	if c := o.Color("#ff8800"); c == ANSIRed {
+		t.Errorf("Expected a color other than %v", ANSIRed)
+	}
+
+	// other profiles are unaffected
+	o = NewOutput(io.Discard, WithProfile(ANSI256), WithPalette(p))
+	if c := o.Color("#ff8800"); c != ANSI256Color(208) {
+		t.Errorf("Expected %v, got %v", ANSI256Color(208), c)
+	}
+}
+
+func TestPaletteHasDarkBackground(t *testing.T) {
+	p := DefaultPalette()
+	p[ANSIBlack] = RGBColor("#ffffff")
+
+	o := NewOutput(io.Discard, WithPalette(p))
+	o.bgColor = ANSIBlack
+	if o.HasDarkBackground() {
+		t.Errorf("Expected light background")
+	}
+}
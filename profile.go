@@ -2,7 +2,6 @@ package termenv
 
 import (
 	"image/color"
-	"strconv"
 	"strings"
 
 	"github.com/lucasb-eyer/go-colorful"
@@ -47,6 +46,14 @@ func (p Profile) String(s ...string) Style {
 
 // Convert transforms a given Color to a Color supported within the Profile.
 func (p Profile) Convert(c Color) Color {
+	return defaultColorCache.convert(p, c, nil, nil)
+}
+
+// convert transforms a given Color to a Color supported within the Profile.
+// If pal is not nil, colors are converted to the closest color of pal
+// instead of xterm's default ANSI colors. Closest colors are picked by q, or
+// by the default quantizer if q is nil.
+func (p Profile) convert(c Color, pal *Palette, q Quantizer) Color {
 	if p == Ascii {
 		return NoColor{}
 	}
@@ -55,9 +62,22 @@ func (p Profile) Convert(c Color) Color {
 	case ANSIColor:
 		return v
 
+	case AdaptiveColor:
+		return AdaptiveColor{
+			Light: p.convert(v.Light, pal, q),
+			Dark:  p.convert(v.Dark, pal, q),
+		}
+
+	case CompleteColor:
+		col, explicit := v.resolve(p)
+		if explicit {
+			return col
+		}
+		return p.convert(col, pal, q)
+
 	case ANSI256Color:
 		if p == ANSI {
-			return ansi256ToANSIColor(v)
+			return toANSIColor(ansiColors[v], pal, q)
 		}
 		return v
 
@@ -66,12 +86,20 @@ func (p Profile) Convert(c Color) Color {
 		if err != nil {
 			return nil
 		}
-		if p != TrueColor {
-			ac := hexToANSI256Color(h)
-			if p == ANSI {
-				return ansi256ToANSIColor(ac)
-			}
-			return ac
+		switch p {
+		case ANSI256:
+			return toANSI256Color(h, q)
+		case ANSI:
+			return rgbToANSIColor(h, pal, q)
+		}
+		return v
+
+	case RGB:
+		switch p {
+		case ANSI256:
+			return toANSI256Color(v.colorful(), q)
+		case ANSI:
+			return rgbToANSIColor(v.colorful(), pal, q)
 		}
 		return v
 	}
@@ -79,30 +107,26 @@ func (p Profile) Convert(c Color) Color {
 	return c
 }
 
-// Color creates a Color from a string. Valid inputs are hex colors, as well as
-// ANSI color codes (0-15, 16-255).
+// Color creates a Color from a string. Valid inputs are all colors understood
+// by ParseColor, such as hex colors, ANSI color codes (0-15, 16-255) and color
+// names. It returns nil for invalid inputs.
 func (p Profile) Color(s string) Color {
-	if len(s) == 0 {
+	c := parseColor(s)
+	if c == nil {
 		return nil
 	}
 
-	var c Color
-	if strings.HasPrefix(s, "#") {
-		c = RGBColor(s)
-	} else {
-		i, err := strconv.Atoi(s)
-		if err != nil {
-			return nil
-		}
+	return p.Convert(c)
+}
 
-		if i < 16 { //nolint:mnd
-			c = ANSIColor(i)
-		} else {
-			c = ANSI256Color(i)
-		}
+// parseColor creates a Color from a string as understood by ParseColor. It
+// returns nil for invalid inputs.
+func parseColor(s string) Color {
+	c, err := ParseColor(s)
+	if err != nil {
+		return nil
 	}
-
-	return p.Convert(c)
+	return c
 }
 
 // FromColor creates a Color from a color.Color.
@@ -0,0 +1,140 @@
+package termenv
+
+import (
+	"math"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// Quantizer picks the closest color of a palette when colors get converted to
+// a profile with fewer colors.
+type Quantizer interface {
+	// Quantize returns the index of the color in palette closest to c.
+	Quantize(c colorful.Color, palette []colorful.Color) int
+}
+
+// LegacyQuantizer picks colors by their HSLuv distance. Converting to the
+// 256-color palette uses the xterm color cube math instead of a palette
+// search. This is the default.
+type LegacyQuantizer struct{}
+
+// Quantize returns the index of the color in palette closest to c.
+func (LegacyQuantizer) Quantize(c colorful.Color, palette []colorful.Color) int {
+	return nearestColor(c, palette, colorful.Color.DistanceHSLuv)
+}
+
+// CIEDE2000Quantizer picks colors by their CIEDE2000 color difference, which
+// is accurate, but slow.
+type CIEDE2000Quantizer struct{}
+
+// Quantize returns the index of the color in palette closest to c.
+func (CIEDE2000Quantizer) Quantize(c colorful.Color, palette []colorful.Color) int {
+	return nearestColor(c, palette, colorful.Color.DistanceCIEDE2000)
+}
+
+// OkLabQuantizer picks colors by their euclidean distance in the OkLab color
+// space, which is a fast approximation of perceived color difference.
+type OkLabQuantizer struct{}
+
+// Quantize returns the index of the color in palette closest to c.
+func (OkLabQuantizer) Quantize(c colorful.Color, palette []colorful.Color) int {
+	return nearestColor(c, palette, distanceOkLab)
+}
+
+// RGBQuantizer picks colors by their euclidean distance in the sRGB color
+// space.
+type RGBQuantizer struct{}
+
+// Quantize returns the index of the color in palette closest to c.
+func (RGBQuantizer) Quantize(c colorful.Color, palette []colorful.Color) int {
+	return nearestColor(c, palette, colorful.Color.DistanceRgb)
+}
+
+// DirectQuantizer wraps a Quantizer to convert RGB colors directly to the 16
+// ANSI colors. By default, they get converted to the 256-color palette first,
+// unless a palette was set with WithPalette.
+type DirectQuantizer struct {
+	Quantizer
+}
+
+// Quantize returns the index of the color in palette closest to c.
+func (q DirectQuantizer) Quantize(c colorful.Color, palette []colorful.Color) int {
+	return quantizer(q.Quantizer).Quantize(c, palette)
+}
+
+// quantizer returns q, or the default quantizer if q is nil.
+func quantizer(q Quantizer) Quantizer {
+	if q == nil {
+		return LegacyQuantizer{}
+	}
+	return q
+}
+
+func nearestColor(c colorful.Color, palette []colorful.Color, dist func(a, b colorful.Color) float64) int {
+	var r int
+	md := math.MaxFloat64
+
+	for i, pc := range palette {
+		if d := dist(c, pc); d < md {
+			md = d
+			r = i
+		}
+	}
+
+	return r
+}
+
+func distanceOkLab(a, b colorful.Color) float64 {
+	l1, a1, b1 := a.OkLab()
+	l2, a2, b2 := b.OkLab()
+	return math.Sqrt(sq(l1-l2) + sq(a1-a2) + sq(b1-b2))
+}
+
+func sq(v float64) float64 {
+	return v * v
+}
+
+// ansiColors are the RGB values of all 256 ANSI colors.
+var ansiColors = func() []colorful.Color {
+	c := make([]colorful.Color, len(ansiHex))
+	for i, h := range ansiHex {
+		c[i], _ = colorful.Hex(h)
+	}
+	return c
+}()
+
+// toANSI256Color converts c to the 256-color palette, ignoring the 16 ANSI
+// colors, as their values depend on the terminal.
+//
+//nolint:mnd
+func toANSI256Color(c colorful.Color, q Quantizer) ANSI256Color {
+	if d, ok := q.(DirectQuantizer); ok {
+		q = d.Quantizer
+	}
+	if _, ok := quantizer(q).(LegacyQuantizer); ok {
+		return hexToANSI256Color(c)
+	}
+	return ANSI256Color(16 + q.Quantize(c, ansiColors[16:]))
+}
+
+// toANSIColor converts c to one of the 16 ANSI colors of pal, or of xterm's
+// default palette if pal is nil.
+//
+//nolint:mnd
+func toANSIColor(c colorful.Color, pal *Palette, q Quantizer) ANSIColor {
+	colors := ansiColors[:16]
+	if pal != nil {
+		colors = pal.colors()
+	}
+	return ANSIColor(quantizer(q).Quantize(c, colors))
+}
+
+// rgbToANSIColor converts an RGB color to one of the 16 ANSI colors. Unless
+// a palette is set or q is a DirectQuantizer, the color gets converted to the
+// 256-color palette first.
+func rgbToANSIColor(c colorful.Color, pal *Palette, q Quantizer) ANSIColor {
+	if _, direct := q.(DirectQuantizer); pal == nil && !direct {
+		c = ansiColors[toANSI256Color(c, q)]
+	}
+	return toANSIColor(c, pal, q)
+}
@@ -0,0 +1,67 @@
+package termenv
+
+import (
+	"io"
+	"testing"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+func TestQuantizers(t *testing.T) {
+	palette := []colorful.Color{
+		{R: 0, G: 0, B: 0},
+		{R: 1, G: 0, B: 0},
+		{R: 1, G: 1, B: 0},
+		{R: 1, G: 1, B: 1},
+	}
+	orange, _ := colorful.Hex("#ff9900")
+
+	tt := []struct {
+		Quantizer Quantizer
+		Expected  int
+	}{
+		{LegacyQuantizer{}, 1},
+		{CIEDE2000Quantizer{}, 1},
+		{OkLabQuantizer{}, 1},
+		{RGBQuantizer{}, 2},
+		{DirectQuantizer{RGBQuantizer{}}, 2},
+		{DirectQuantizer{}, 1},
+	}
+
+	for _, test := range tt {
+		t.Run("", func(t *testing.T) {
+			if i := test.Quantizer.Quantize(orange, palette); i != test.Expected {
+				t.Errorf("%T: expected %d, got %d", test.Quantizer, test.Expected, i)
+			}
+		})
+	}
+}
+
+func TestWithQuantizer(t *testing.T) {
+	tt := []struct {
+		Profile   Profile
+		Quantizer Quantizer
+		Color     Color
+		Expected  Color
+	}{
+		{ANSI256, nil, RGBColor("#c86432"), ANSI256Color(167)},
+		{ANSI256, LegacyQuantizer{}, RGBColor("#c86432"), ANSI256Color(167)},
+		{ANSI256, OkLabQuantizer{}, RGBColor("#c86432"), ANSI256Color(166)},
+		{ANSI256, CIEDE2000Quantizer{}, RGBColor("#c86432"), ANSI256Color(166)},
+		{ANSI, nil, RGBColor("#5f87af"), ANSIBrightBlue},
+		{ANSI, OkLabQuantizer{}, RGBColor("#5f87af"), ANSIBrightBlack},
+		{ANSI, RGBQuantizer{}, RGBColor("#ff8800"), ANSIBrightYellow},
+		{ANSI, OkLabQuantizer{}, RGBColor("#0022bb"), ANSIBlue},
+		{ANSI, DirectQuantizer{OkLabQuantizer{}}, RGBColor("#0022bb"), ANSIBrightBlue},
+		{ANSI, RGBQuantizer{}, ANSI256Color(208), ANSIBrightYellow},
+	}
+
+	for _, test := range tt {
+		t.Run("", func(t *testing.T) {
+			o := NewOutput(io.Discard, WithProfile(test.Profile), WithQuantizer(test.Quantizer))
+			if c := o.Convert(test.Color); c != test.Expected {
+				t.Errorf("%T: expected %#v, got %#v", test.Quantizer, test.Expected, c)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package termenv
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// timeout for OSC queries.
+	OSCTimeout = 5 * time.Second
+
+	// maxReplyLength limits how many bytes we read for a single reply.
+	maxReplyLength = 4096
+)
+
+// ReplyType is the type of control sequence a terminal replied with.
+type ReplyType int
+
+// Reply types.
+const (
+	// CSIReply is a Control Sequence Introducer reply, e.g. "\x1b[42;1R".
+	CSIReply ReplyType = iota
+	// OSCReply is an Operating System Command reply, e.g. "\x1b]11;rgb:0000/0000/0000\x1b\\".
+	OSCReply
+	// DCSReply is a Device Control String reply, e.g. "\x1bP>|xterm(390)\x1b\\".
+	DCSReply
+	// APCReply is an Application Program Command reply, e.g. "\x1b_Gi=1;OK\x1b\\".
+	APCReply
+)
+
+// replyIntroducers maps the byte following ESC to the type of reply it
+// introduces.
+var replyIntroducers = map[byte]ReplyType{
+	'[': CSIReply,
+	']': OSCReply,
+	'P': DCSReply,
+	'_': APCReply,
+}
+
+// Reply is a control sequence the terminal sent in response to a query.
+type Reply struct {
+	Type ReplyType
+
+	// Payload is the content of the sequence without its introducer and
+	// string terminator. For CSI replies it includes the final byte, e.g.
+	// "42;1R".
+	Payload string
+}
+
+// String returns the reply as a control sequence.
+func (r Reply) String() string {
+	switch r.Type {
+	case CSIReply:
+		return CSI + r.Payload
+	case OSCReply:
+		return OSC + r.Payload + ST
+	case DCSReply:
+		return DCS + r.Payload + ST
+	case APCReply:
+		return APC + r.Payload + ST
+	}
+	return ""
+}
+
+// ReplyMatcher reports whether a reply answers a query.
+type ReplyMatcher func(Reply) bool
+
+// isPrimaryDeviceAttributes reports whether r is a DA1 reply: "\x1b[?62;22c".
+func isPrimaryDeviceAttributes(r Reply) bool {
+	return r.Type == CSIReply &&
+		strings.HasPrefix(r.Payload, "?") &&
+		strings.HasSuffix(r.Payload, "c")
+}
+
+// Query writes the request to the terminal, followed by a Primary Device
+// Attributes (DA1) request. As virtually all terminals answer DA1, and do so
+// in order, its reply marks the end of the replies to request. Every reply
+// read up to and including the DA1 reply is passed to match, and the
+// accepted replies are returned. A nil match accepts all replies.
+//
+// Query honors the deadline and cancellation of ctx. If ctx has no deadline,
+// OSCTimeout is used.
+//
+// The request must not contain a DA1 request itself. In unsafe mode, a read
+// that is still pending when ctx expires consumes the next byte sent by the
+// terminal.
+func (o *Output) Query(ctx context.Context, request string, match ReplyMatcher) ([]Reply, error) {
+	tty := o.TTY()
+	if tty == nil {
+		return nil, ErrStatusReport
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, OSCTimeout)
+		defer cancel()
+	}
+
+	restore, err := o.disableEcho(tty)
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+
+	if _, err := io.WriteString(tty, request+CSI+"c"); err != nil {
+		return nil, fmt.Errorf("%s: %s", ErrStatusReport, err)
+	}
+
+	var replies []Reply
+	for {
+		r, err := readReply(func() (byte, error) {
+			return o.readByte(ctx, tty)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if match == nil || match(r) {
+			replies = append(replies, r)
+		}
+		if isPrimaryDeviceAttributes(r) {
+			return replies, nil
+		}
+	}
+}
+
+// canQueryOSC reports whether OSC queries can be answered by the terminal.
+func (o Output) canQueryOSC() bool {
+	// screen/tmux can't support OSC, because they can be connected to multiple
+	// terminals concurrently.
+	term := o.environ.Getenv("TERM")
+	return !strings.HasPrefix(term, "screen") && !strings.HasPrefix(term, "tmux") && !strings.HasPrefix(term, "dumb")
+}
+
+// Query writes the request to the terminal and returns the replies accepted by
+// match. See Output.Query for details.
+func Query(ctx context.Context, request string, match ReplyMatcher) ([]Reply, error) {
+	return output.Query(ctx, request, match)
+}
+
+func (o *Output) readByte(ctx context.Context, tty File) (byte, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	if o.unsafe {
+		// we can't wait for data on the file descriptor, so read in the
+		// background and give up once the context is done
+		type result struct {
+			b   byte
+			err error
+		}
+		ch := make(chan result, 1)
+		go func() {
+			b, err := readNextByte(tty)
+			ch <- result{b, err}
+		}()
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err() //nolint:wrapcheck
+		case r := <-ch:
+			return r.b, r.err
+		}
+	}
+
+	if err := o.waitForData(ctx, tty); err != nil {
+		return 0, err
+	}
+	return readNextByte(tty)
+}
+
+func readNextByte(r io.Reader) (byte, error) {
+	var b [1]byte
+	n, err := r.Read(b[:])
+	if err != nil {
+		return 0, fmt.Errorf("%s: %s", ErrStatusReport, err)
+	}
+
+	if n == 0 {
+		panic("read returned no data")
+	}
+
+	return b[0], nil
+}
+
+// readReply reads the next CSI, OSC, DCS, or APC sequence. Any other input,
+// like key presses, is skipped.
+func readReply(next func() (byte, error)) (Reply, error) {
+	var r Reply
+	b, err := next()
+	if err != nil {
+		return r, err
+	}
+
+	for {
+		// first byte must be ESC
+		for b != ESC {
+			if b, err = next(); err != nil {
+				return r, err
+			}
+		}
+
+		if b, err = next(); err != nil {
+			return r, err
+		}
+
+		if t, ok := replyIntroducers[b]; ok {
+			r.Type = t
+			break
+		}
+	}
+
+	var payload strings.Builder
+	for payload.Len() < maxReplyLength {
+		if b, err = next(); err != nil {
+			return r, err
+		}
+
+		if r.Type == CSIReply {
+			payload.WriteByte(b)
+
+			// CSI sequences are terminated by a final byte in the range @ to ~
+			if b >= '@' && b <= '~' {
+				r.Payload = payload.String()
+				return r, nil
+			}
+			continue
+		}
+
+		// strings are terminated by ST (ESC \) or BEL
+		switch b {
+		case ESC:
+			if _, err = next(); err != nil {
+				return r, err
+			}
+			fallthrough
+		case BEL:
+			r.Payload = payload.String()
+			return r, nil
+		}
+
+		payload.WriteByte(b)
+	}
+
+	return r, ErrStatusReport
+}
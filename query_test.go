@@ -0,0 +1,64 @@
+package termenv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadReply(t *testing.T) {
+	tests := []struct {
+		input string
+		reply Reply
+		valid bool
+	}{
+		{"\x1b[42;1R", Reply{CSIReply, "42;1R"}, true},
+		{"\x1b[?62;22c", Reply{CSIReply, "?62;22c"}, true},
+		{"\x1b]11;rgb:1212/3434/5656\a", Reply{OSCReply, "11;rgb:1212/3434/5656"}, true},
+		{"\x1b]11;rgb:1212/3434/5656\x1b\\", Reply{OSCReply, "11;rgb:1212/3434/5656"}, true},
+		{"\x1bP>|xterm(390)\x1b\\", Reply{DCSReply, ">|xterm(390)"}, true},
+		{"\x1b_Gi=1;OK\x1b\\", Reply{APCReply, "Gi=1;OK"}, true},
+		// skip key presses and other unrelated input
+		{"abc\x1bOA\x1b[42;1R", Reply{CSIReply, "42;1R"}, true},
+		{"\x1b\x1b[42;1R", Reply{CSIReply, "42;1R"}, true},
+		// incomplete replies
+		{"\x1b[42;1", Reply{}, false},
+		{"\x1b]11;rgb:1212/3434/5656", Reply{}, false},
+		{"\x1b]" + strings.Repeat("x", maxReplyLength+1), Reply{}, false},
+	}
+
+	for _, test := range tests {
+		t.Run("", func(t *testing.T) {
+			rd := strings.NewReader(test.input)
+			r, err := readReply(func() (byte, error) {
+				return readNextByte(rd)
+			})
+			if err != nil && test.valid {
+				t.Fatalf("unexpected error for input %q: %v", test.input, err)
+			}
+			if err == nil && !test.valid {
+				t.Fatalf("expected error for input %q not found", test.input)
+			}
+			if test.valid && r != test.reply {
+				t.Fatalf("wrong reply returned, want %+v, got %+v", test.reply, r)
+			}
+		})
+	}
+}
+
+func TestReplyString(t *testing.T) {
+	tests := []struct {
+		reply Reply
+		exp   string
+	}{
+		{Reply{CSIReply, "42;1R"}, "\x1b[42;1R"},
+		{Reply{OSCReply, "11;rgb:1212/3434/5656"}, "\x1b]11;rgb:1212/3434/5656\x1b\\"},
+		{Reply{DCSReply, ">|xterm(390)"}, "\x1bP>|xterm(390)\x1b\\"},
+		{Reply{APCReply, "Gi=1;OK"}, "\x1b_Gi=1;OK\x1b\\"},
+	}
+
+	for _, test := range tests {
+		if s := test.reply.String(); s != test.exp {
+			t.Errorf("Expected %q, got %q", test.exp, s)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package termenv
 
 import (
 	"fmt"
+	"io"
 	"strings"
 )
 
@@ -60,6 +61,17 @@ const (
 	StartBracketedPasteSeq   = "200~"
 	EndBracketedPasteSeq     = "201~"
 
+	// Synchronized output.
+	// https://gist.github.com/christianparpart/d8a62cc1ab659194337d73e399004036
+	BeginSynchronizedUpdateSeq = "?2026h"
+	EndSynchronizedUpdateSeq   = "?2026l"
+
+	// Color scheme reports.
+	// https://contour-terminal.org/vt-extensions/color-palette-update-notifications/
+	RequestColorSchemeSeq        = "?996n"
+	EnableColorSchemeUpdatesSeq  = "?2031h"
+	DisableColorSchemeUpdatesSeq = "?2031l"
+
 	// Session.
 	SetWindowTitleSeq     = "2;%s" + string(BEL)
 	SetForegroundColorSeq = "10;%s" + string(BEL)
@@ -67,6 +79,32 @@ const (
 	SetCursorColorSeq     = "12;%s" + string(BEL)
 	ShowCursorSeq         = "?25h"
 	HideCursorSeq         = "?25l"
+	SetCursorStyleSeq     = "%d q"
+)
+
+// EraseDisplayMode determines which part of the display gets erased.
+type EraseDisplayMode int
+
+// Explicit values for EraseDisplaySeq.
+const (
+	EraseDisplayBelow EraseDisplayMode = iota
+	EraseDisplayAbove
+	EraseDisplayAll
+	EraseDisplayScrollback
+)
+
+// CursorStyle is the shape of the cursor.
+type CursorStyle int
+
+// Cursor styles.
+const (
+	DefaultCursor CursorStyle = iota
+	BlinkingBlockCursor
+	SteadyBlockCursor
+	BlinkingUnderlineCursor
+	SteadyUnderlineCursor
+	BlinkingBarCursor
+	SteadyBarCursor
 )
 
 // Reset the terminal to its default style, removing any active styles.
@@ -132,6 +170,11 @@ func (o Output) ShowCursor() {
 	fmt.Fprint(o.w, CSI+ShowCursorSeq) //nolint:errcheck
 }
 
+// SetCursorStyle sets the shape of the cursor, and whether it blinks.
+func (o Output) SetCursorStyle(style CursorStyle) {
+	fmt.Fprintf(o.w, CSI+SetCursorStyleSeq, style) //nolint:errcheck
+}
+
 // SaveCursorPosition saves the cursor position.
 func (o Output) SaveCursorPosition() {
 	fmt.Fprint(o.w, CSI+SaveCursorPositionSeq) //nolint:errcheck
@@ -174,6 +217,29 @@ func (o Output) CursorPrevLine(n int) {
 	fmt.Fprintf(o.w, CSI+CursorPreviousLineSeq, n) //nolint:errcheck
 }
 
+// CursorColumn moves the cursor to the given column of the current line.
+func (o Output) CursorColumn(col int) {
+	fmt.Fprintf(o.w, CSI+CursorHorizontalSeq, col) //nolint:errcheck
+}
+
+// ScrollUp scrolls the content of the scrollable region up by the given number
+// of lines. New lines are added at the bottom.
+func (o Output) ScrollUp(n int) {
+	fmt.Fprintf(o.w, CSI+ScrollUpSeq, n) //nolint:errcheck
+}
+
+// ScrollDown scrolls the content of the scrollable region down by the given
+// number of lines. New lines are added at the top.
+func (o Output) ScrollDown(n int) {
+	fmt.Fprintf(o.w, CSI+ScrollDownSeq, n) //nolint:errcheck
+}
+
+// EraseDisplay erases part of the display: below or above the cursor, the
+// entire visible portion, or the scrollback buffer.
+func (o Output) EraseDisplay(mode EraseDisplayMode) {
+	fmt.Fprintf(o.w, CSI+EraseDisplaySeq, mode) //nolint:errcheck
+}
+
 // ClearLine clears the current line.
 func (o Output) ClearLine() {
 	fmt.Fprint(o.w, CSI+EraseEntireLineSeq) //nolint:errcheck
@@ -301,6 +367,45 @@ func (o Output) DisableBracketedPaste() {
 	fmt.Fprintf(o.w, CSI+DisableBracketedPasteSeq) //nolint:errcheck
 }
 
+// EnableColorSchemeUpdates makes the terminal report changes of its color
+// scheme, e.g. when the system switches between light and dark mode. See
+// ParseColorSchemeReport.
+func (o Output) EnableColorSchemeUpdates() {
+	fmt.Fprint(o.w, CSI+EnableColorSchemeUpdatesSeq) //nolint:errcheck
+}
+
+// DisableColorSchemeUpdates stops reporting changes of the color scheme.
+func (o Output) DisableColorSchemeUpdates() {
+	fmt.Fprint(o.w, CSI+DisableColorSchemeUpdatesSeq) //nolint:errcheck
+}
+
+// BeginSynchronizedUpdate starts a synchronized update. The terminal holds
+// back rendering until EndSynchronizedUpdate gets called, so the output in
+// between appears at once, without flickering.
+func (o Output) BeginSynchronizedUpdate() {
+	fmt.Fprint(o.w, CSI+BeginSynchronizedUpdateSeq) //nolint:errcheck
+}
+
+// EndSynchronizedUpdate ends a synchronized update and renders its output.
+func (o Output) EndSynchronizedUpdate() {
+	fmt.Fprint(o.w, CSI+EndSynchronizedUpdateSeq) //nolint:errcheck
+}
+
+// Synchronized calls fn with the Output's writer, wrapped in a synchronized
+// update. The update always gets ended, even if fn returns an error or
+// panics. On Ascii profiles or non-TTY outputs, fn gets called without
+// starting an update.
+func (o Output) Synchronized(fn func(w io.Writer) error) error {
+	if o.Profile == Ascii || !o.isTTY() {
+		return fn(o.w)
+	}
+
+	o.BeginSynchronizedUpdate()
+	defer o.EndSynchronizedUpdate()
+
+	return fn(o.w)
+}
+
 // Legacy functions.
 
 // Reset the terminal to its default style, removing any active styles.
@@ -389,6 +494,13 @@ func ShowCursor() {
 	output.ShowCursor()
 }
 
+// SetCursorStyle sets the shape of the cursor, and whether it blinks.
+//
+// Deprecated: please use termenv.Output instead.
+func SetCursorStyle(style CursorStyle) {
+	output.SetCursorStyle(style)
+}
+
 // SaveCursorPosition saves the cursor position.
 //
 // Deprecated: please use termenv.Output instead.
@@ -447,6 +559,37 @@ func CursorPrevLine(n int) {
 	output.CursorPrevLine(n)
 }
 
+// CursorColumn moves the cursor to the given column of the current line.
+//
+// Deprecated: please use termenv.Output instead.
+func CursorColumn(col int) {
+	output.CursorColumn(col)
+}
+
+// ScrollUp scrolls the content of the scrollable region up by the given number
+// of lines. New lines are added at the bottom.
+//
+// Deprecated: please use termenv.Output instead.
+func ScrollUp(n int) {
+	output.ScrollUp(n)
+}
+
+// ScrollDown scrolls the content of the scrollable region down by the given
+// number of lines. New lines are added at the top.
+//
+// Deprecated: please use termenv.Output instead.
+func ScrollDown(n int) {
+	output.ScrollDown(n)
+}
+
+// EraseDisplay erases part of the display: below or above the cursor, the
+// entire visible portion, or the scrollback buffer.
+//
+// Deprecated: please use termenv.Output instead.
+func EraseDisplay(mode EraseDisplayMode) {
+	output.EraseDisplay(mode)
+}
+
 // ClearLine clears the current line.
 //
 // Deprecated: please use termenv.Output instead.
@@ -2,6 +2,7 @@ package termenv
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"os"
 	"strings"
@@ -126,6 +127,18 @@ func TestShowCursor(t *testing.T) {
 	verify(t, o, "\x1b[?25h")
 }
 
+func TestSetCursorStyle(t *testing.T) {
+	o := tempOutput(t)
+	o.SetCursorStyle(SteadyBarCursor)
+	verify(t, o, "\x1b[6 q")
+}
+
+func TestSetDefaultCursorStyle(t *testing.T) {
+	o := tempOutput(t)
+	o.SetCursorStyle(DefaultCursor)
+	verify(t, o, "\x1b[0 q")
+}
+
 func TestSaveCursorPosition(t *testing.T) {
 	o := tempOutput(t)
 	o.SaveCursorPosition()
@@ -174,6 +187,42 @@ func TestCursorPrevLine(t *testing.T) {
 	verify(t, o, "\x1b[8F")
 }
 
+func TestCursorColumn(t *testing.T) {
+	o := tempOutput(t)
+	o.CursorColumn(8)
+	verify(t, o, "\x1b[8G")
+}
+
+func TestScrollUp(t *testing.T) {
+	o := tempOutput(t)
+	o.ScrollUp(8)
+	verify(t, o, "\x1b[8S")
+}
+
+func TestScrollDown(t *testing.T) {
+	o := tempOutput(t)
+	o.ScrollDown(8)
+	verify(t, o, "\x1b[8T")
+}
+
+func TestEraseDisplay(t *testing.T) {
+	tests := []struct {
+		mode EraseDisplayMode
+		exp  string
+	}{
+		{EraseDisplayBelow, "\x1b[0J"},
+		{EraseDisplayAbove, "\x1b[1J"},
+		{EraseDisplayAll, "\x1b[2J"},
+		{EraseDisplayScrollback, "\x1b[3J"},
+	}
+
+	for _, test := range tests {
+		o := tempOutput(t)
+		o.EraseDisplay(test.mode)
+		verify(t, o, test.exp)
+	}
+}
+
 func TestClearLine(t *testing.T) {
 	o := tempOutput(t)
 	o.ClearLine()
@@ -306,6 +355,72 @@ func TestSetWindowTitle(t *testing.T) {
 	verify(t, o, "\x1b]2;test\a")
 }
 
+func TestBeginSynchronizedUpdate(t *testing.T) {
+	o := tempOutput(t)
+	o.BeginSynchronizedUpdate()
+	verify(t, o, "\x1b[?2026h")
+}
+
+func TestEndSynchronizedUpdate(t *testing.T) {
+	o := tempOutput(t)
+	o.EndSynchronizedUpdate()
+	verify(t, o, "\x1b[?2026l")
+}
+
+func TestSynchronized(t *testing.T) {
+	o := tempOutput(t)
+	o.assumeTTY = true
+	err := o.Synchronized(func(w io.Writer) error {
+		_, err := io.WriteString(w, "frame")
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify(t, o, "\x1b[?2026hframe\x1b[?2026l")
+}
+
+func TestSynchronizedError(t *testing.T) {
+	o := tempOutput(t)
+	o.assumeTTY = true
+	exp := errors.New("failed")
+	err := o.Synchronized(func(w io.Writer) error {
+		return exp
+	})
+	if err != exp {
+		t.Errorf("Expected %v, got %v", exp, err)
+	}
+	verify(t, o, "\x1b[?2026h\x1b[?2026l")
+}
+
+func TestSynchronizedPanic(t *testing.T) {
+	o := tempOutput(t)
+	o.assumeTTY = true
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Expected panic to be propagated")
+			}
+		}()
+		_ = o.Synchronized(func(w io.Writer) error {
+			panic("failed")
+		})
+	}()
+	verify(t, o, "\x1b[?2026h\x1b[?2026l")
+}
+
+func TestSynchronizedNoTTY(t *testing.T) {
+	o := tempOutput(t)
+	err := o.Synchronized(func(w io.Writer) error {
+		_, err := io.WriteString(w, "frame")
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify(t, o, "frame")
+}
+
 func TestCopyClipboard(t *testing.T) {
 	o := tempOutput(t)
 	o.Copy("hello")
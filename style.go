@@ -9,22 +9,102 @@ import (
 
 // Sequence definitions.
 const (
-	ResetSeq     = "0"
-	BoldSeq      = "1"
-	FaintSeq     = "2"
-	ItalicSeq    = "3"
-	UnderlineSeq = "4"
-	BlinkSeq     = "5"
-	ReverseSeq   = "7"
-	CrossOutSeq  = "9"
-	OverlineSeq  = "53"
+	ResetSeq       = "0"
+	BoldSeq        = "1"
+	FaintSeq       = "2"
+	ItalicSeq      = "3"
+	UnderlineSeq   = "4"
+	BlinkSeq       = "5"
+	RapidBlinkSeq  = "6"
+	ReverseSeq     = "7"
+	ConcealSeq     = "8"
+	CrossOutSeq    = "9"
+	OverlineSeq    = "53"
+	SuperscriptSeq = "73"
+	SubscriptSeq   = "74"
+
+	UnderlineStyleSeq = "4:%d"
+	NoUnderlineSeq    = "24"
+)
+
+// UnderlineStyle is the style of an underline.
+type UnderlineStyle int
+
+// Underline styles.
+const (
+	NoUnderline UnderlineStyle = iota
+	SingleUnderline
+	DoubleUnderline
+	CurlyUnderline
+	DottedUnderline
+	DashedUnderline
 )
 
+// underlineColor is implemented by colors that can be used for underlines.
+type underlineColor interface {
+	UnderlineSequence() string
+}
+
+// Attribute is a set of text attributes, such as bold or italic rendering.
+type Attribute uint32
+
+// Text attributes.
+const (
+	BoldAttribute Attribute = 1 << iota
+	FaintAttribute
+	ItalicAttribute
+	UnderlineAttribute
+	BlinkAttribute
+	RapidBlinkAttribute
+	ReverseAttribute
+	ConcealAttribute
+	CrossOutAttribute
+	OverlineAttribute
+	SuperscriptAttribute
+	SubscriptAttribute
+)
+
+// attributeSequences lists the sequences of all attributes in the order they
+// get rendered.
+var attributeSequences = []struct {
+	attr Attribute
+	seq  string
+}{
+	{BoldAttribute, BoldSeq},
+	{FaintAttribute, FaintSeq},
+	{ItalicAttribute, ItalicSeq},
+	{UnderlineAttribute, UnderlineSeq},
+	{BlinkAttribute, BlinkSeq},
+	{RapidBlinkAttribute, RapidBlinkSeq},
+	{ReverseAttribute, ReverseSeq},
+	{ConcealAttribute, ConcealSeq},
+	{CrossOutAttribute, CrossOutSeq},
+	{OverlineAttribute, OverlineSeq},
+	{SuperscriptAttribute, SuperscriptSeq},
+	{SubscriptAttribute, SubscriptSeq},
+}
+
 // Style is a string that various rendering styles can be applied to.
+//
+// Every property of a Style is either set, unset or inherited. Set properties
+// get rendered, while unset and inherited ones don't. The difference only
+// matters when merging styles: unset properties override the other style,
+// whereas inherited properties are taken from it. Setting a property again
+// replaces its previous value.
+//
+// Adaptive colors get resolved against the cached background color of the
+// Output a Style was created with, see Output.String. Rendering never queries
+// the terminal, so without an Output or WithColorCache, adaptive colors use
+// their Dark color.
 type Style struct {
 	profile Profile
+	output  *Output
 	string
-	styles []string
+
+	fg, bg, ul Color
+	attrs      Attribute
+	unset      Attribute
+	underline  UnderlineStyle
 }
 
 // String returns a new Style.
@@ -44,83 +124,361 @@ func (t Style) Styled(s string) string {
 	if t.profile == Ascii {
 		return s
 	}
-	if len(t.styles) == 0 {
-		return s
-	}
 
-	seq := strings.Join(t.styles, ";")
+	seq := t.sequence()
 	if seq == "" {
 		return s
 	}
 
-	return fmt.Sprintf("%s%sm%s%sm", CSI, seq, s, CSI+ResetSeq)
+	return fmt.Sprintf("%s%sm%s%sm", CSI, seq, restoreStyle(s, seq), CSI+ResetSeq)
+}
+
+// sequence returns the SGR parameters needed to render the style.
+func (t Style) sequence() string {
+	dark := true
+	if t.hasAdaptiveColor() {
+		dark = t.darkBackground()
+	}
+
+	var seqs []string
+	if fg := t.color(t.fg, dark); fg != nil {
+		seqs = append(seqs, fg.Sequence(false))
+	}
+	if bg := t.color(t.bg, dark); bg != nil {
+		seqs = append(seqs, bg.Sequence(true))
+	}
+
+	for _, a := range attributeSequences {
+		if t.attrs&a.attr == 0 {
+			continue
+		}
+		if a.attr == UnderlineAttribute && t.underline != SingleUnderline && t.profile != ANSI {
+			seqs = append(seqs, fmt.Sprintf(UnderlineStyleSeq, t.underline))
+			continue
+		}
+		seqs = append(seqs, a.seq)
+	}
+
+	if u, ok := t.color(t.ul, dark).(underlineColor); ok && t.profile != ANSI {
+		seqs = append(seqs, u.UnderlineSequence())
+	}
+
+	// skip colors without a sequence, such as NoColor
+	n := 0
+	for _, seq := range seqs {
+		if seq != "" {
+			seqs[n] = seq
+			n++
+		}
+	}
+	return strings.Join(seqs[:n], ";")
+}
+
+// color resolves adaptive and complete colors for rendering. Adaptive colors
+// use their Dark color if dark is set.
+func (t Style) color(c Color, dark bool) Color {
+	switch v := c.(type) {
+	case AdaptiveColor:
+		c = v.resolve(dark)
+		if t.output != nil {
+			return t.convert(c)
+		}
+		return t.color(c, dark)
+	case CompleteColor:
+		return t.convert(v)
+	}
+	return c
+}
+
+// hasAdaptiveColor reports whether any color of the style is adaptive.
+func (t Style) hasAdaptiveColor() bool {
+	for _, c := range []Color{t.fg, t.bg, t.ul} {
+		if _, ok := c.(AdaptiveColor); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// darkBackground reports whether adaptive colors should use their Dark color.
+// It never queries the terminal: unless the style's Output caches its colors,
+// a dark background is assumed.
+func (t Style) darkBackground() bool {
+	if t.output == nil || !t.output.cache {
+		return true
+	}
+	return t.output.HasDarkBackground()
 }
 
-// Foreground sets a foreground color.
+// convert transforms c to a Color supported by the style's profile, using the
+// palette and quantizer of its Output, if any.
+func (t Style) convert(c Color) Color {
+	if t.output != nil {
+		return t.output.Convert(c)
+	}
+	return t.profile.Convert(c)
+}
+
+// Foreground sets a foreground color. Passing NoColor unsets the foreground
+// color, while nil is ignored.
 func (t Style) Foreground(c Color) Style {
 	if c != nil {
-		t.styles = append(t.styles, c.Sequence(false))
+		t.fg = c
 	}
 	return t
 }
 
-// Background sets a background color.
+// Background sets a background color. Passing NoColor unsets the background
+// color, while nil is ignored.
 func (t Style) Background(c Color) Style {
 	if c != nil {
-		t.styles = append(t.styles, c.Sequence(true))
+		t.bg = c
 	}
 	return t
 }
 
+// InheritForeground makes the foreground color inherited again, so that it
+// gets taken from the other style when merging styles.
+func (t Style) InheritForeground() Style {
+	t.fg = nil
+	return t
+}
+
+// InheritBackground makes the background color inherited again, so that it
+// gets taken from the other style when merging styles.
+func (t Style) InheritBackground() Style {
+	t.bg = nil
+	return t
+}
+
 // Bold enables bold rendering.
 func (t Style) Bold() Style {
-	t.styles = append(t.styles, BoldSeq)
-	return t
+	return t.set(BoldAttribute)
 }
 
 // Faint enables faint rendering.
 func (t Style) Faint() Style {
-	t.styles = append(t.styles, FaintSeq)
-	return t
+	return t.set(FaintAttribute)
 }
 
 // Italic enables italic rendering.
 func (t Style) Italic() Style {
-	t.styles = append(t.styles, ItalicSeq)
-	return t
+	return t.set(ItalicAttribute)
 }
 
 // Underline enables underline rendering.
 func (t Style) Underline() Style {
-	t.styles = append(t.styles, UnderlineSeq)
+	return t.UnderlineStyle(SingleUnderline)
+}
+
+// UnderlineStyle enables underline rendering in the given style, e.g. curly
+// or dotted. As terminals with basic color support usually lack styled
+// underlines, they get rendered as plain underlines on the ANSI profile.
+// NoUnderline unsets underline rendering, and unknown styles are treated as
+// SingleUnderline.
+func (t Style) UnderlineStyle(u UnderlineStyle) Style {
+	if u == NoUnderline {
+		return t.Unset(UnderlineAttribute)
+	}
+	if u < SingleUnderline || u > DashedUnderline {
+		u = SingleUnderline
+	}
+
+	t.underline = u
+	return t.set(UnderlineAttribute)
+}
+
+// UnderlineColor sets the color of underlines. It is ignored on the ANSI
+// profile, as terminals with basic color support usually lack colored
+// underlines. Passing NoColor unsets the underline color, while nil is
+// ignored.
+func (t Style) UnderlineColor(c Color) Style {
+	if c != nil {
+		t.ul = c
+	}
+	return t
+}
+
+// InheritUnderlineColor makes the underline color inherited again, so that it
+// gets taken from the other style when merging styles.
+func (t Style) InheritUnderlineColor() Style {
+	t.ul = nil
 	return t
 }
 
 // Overline enables overline rendering.
 func (t Style) Overline() Style {
-	t.styles = append(t.styles, OverlineSeq)
-	return t
+	return t.set(OverlineAttribute)
 }
 
 // Blink enables blink mode.
 func (t Style) Blink() Style {
-	t.styles = append(t.styles, BlinkSeq)
-	return t
+	return t.set(BlinkAttribute)
+}
+
+// RapidBlink enables rapid blink mode.
+func (t Style) RapidBlink() Style {
+	return t.set(RapidBlinkAttribute)
 }
 
 // Reverse enables reverse color mode.
 func (t Style) Reverse() Style {
-	t.styles = append(t.styles, ReverseSeq)
-	return t
+	return t.set(ReverseAttribute)
 }
 
 // CrossOut enables crossed-out rendering.
 func (t Style) CrossOut() Style {
-	t.styles = append(t.styles, CrossOutSeq)
+	return t.set(CrossOutAttribute)
+}
+
+// Conceal hides the text, while still taking up space. This is useful e.g.
+// for password prompts.
+func (t Style) Conceal() Style {
+	return t.set(ConcealAttribute)
+}
+
+// DoubleUnderline enables double underline rendering. It is a shorthand for
+// UnderlineStyle(DoubleUnderline).
+func (t Style) DoubleUnderline() Style {
+	return t.UnderlineStyle(DoubleUnderline)
+}
+
+// Superscript enables superscript rendering.
+func (t Style) Superscript() Style {
+	return t.set(SuperscriptAttribute)
+}
+
+// Subscript enables subscript rendering.
+func (t Style) Subscript() Style {
+	return t.set(SubscriptAttribute)
+}
+
+func (t Style) set(a Attribute) Style {
+	t.attrs |= a
+	t.unset &^= a
+	return t
+}
+
+// Unset disables the given attributes, overriding them when merging styles.
+func (t Style) Unset(a Attribute) Style {
+	t.attrs &^= a
+	t.unset |= a
+	return t
+}
+
+// Inherit makes the given attributes inherited again, so that they get taken
+// from the other style when merging styles.
+func (t Style) Inherit(a Attribute) Style {
+	t.attrs &^= a
+	t.unset &^= a
+	return t
+}
+
+// Merge returns a copy of t with all properties set or unset in other
+// applied on top of it. Properties other inherits are kept from t.
+func (t Style) Merge(other Style) Style {
+	if other.fg != nil {
+		t.fg = other.fg
+	}
+	if other.bg != nil {
+		t.bg = other.bg
+	}
+	if other.ul != nil {
+		t.ul = other.ul
+	}
+	if other.attrs&UnderlineAttribute != 0 {
+		t.underline = other.underline
+	}
+
+	mask := other.attrs | other.unset
+	t.attrs = t.attrs&^mask | other.attrs
+	t.unset = t.unset&^mask | other.unset
 	return t
 }
 
+// Has reports whether all of the given attributes are enabled.
+func (t Style) Has(a Attribute) bool {
+	return t.attrs&a == a
+}
+
+// IsUnset reports whether all of the given attributes are explicitly unset.
+func (t Style) IsUnset(a Attribute) bool {
+	return t.unset&a == a
+}
+
+// Attributes returns the enabled attributes.
+func (t Style) Attributes() Attribute {
+	return t.attrs
+}
+
+// GetForeground returns the foreground color, or nil if it is inherited.
+func (t Style) GetForeground() Color {
+	return t.fg
+}
+
+// GetBackground returns the background color, or nil if it is inherited.
+func (t Style) GetBackground() Color {
+	return t.bg
+}
+
+// GetUnderlineColor returns the underline color, or nil if it is inherited.
+func (t Style) GetUnderlineColor() Color {
+	return t.ul
+}
+
+// GetUnderlineStyle returns the underline style, or NoUnderline if underline
+// rendering is not enabled.
+func (t Style) GetUnderlineStyle() UnderlineStyle {
+	if t.attrs&UnderlineAttribute == 0 {
+		return NoUnderline
+	}
+	return t.underline
+}
+
 // Width returns the width required to print all runes in Style.
 func (t Style) Width() int {
 	return uniseg.StringWidth(t.string)
 }
+
+// restoreStyle re-emits seq after every reset within s, so that styled
+// strings nested in s don't end the enclosing style early. A reset at the
+// very end of s is left alone, as it will be followed by our own reset.
+func restoreStyle(s, seq string) string {
+	if !strings.Contains(s, CSI) {
+		return s
+	}
+
+	var b strings.Builder
+	for {
+		i, n := indexReset(s)
+		if i < 0 || i+n == len(s) {
+			b.WriteString(s)
+			return b.String()
+		}
+
+		b.WriteString(s[:i+n])
+		b.WriteString(CSI + seq + "m")
+		s = s[i+n:]
+	}
+}
+
+// indexReset returns the index and length of the first reset sequence in s,
+// or -1 if s contains none.
+func indexReset(s string) (int, int) {
+	for i := strings.Index(s, CSI); i >= 0; {
+		rest := s[i+len(CSI):]
+		switch {
+		case strings.HasPrefix(rest, ResetSeq+"m"):
+			return i, len(CSI + ResetSeq + "m")
+		case strings.HasPrefix(rest, "m"):
+			return i, len(CSI + "m")
+		}
+
+		j := strings.Index(rest, CSI)
+		if j < 0 {
+			break
+		}
+		i += len(CSI) + j
+	}
+	return -1, 0
+}
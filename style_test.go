@@ -26,3 +26,267 @@ func TestStyleWidth(t *testing.T) {
 		t.Errorf("Expected width of 11, got %d", s.Width())
 	}
 }
+
+func TestUnderlineStyle(t *testing.T) {
+	tests := []struct {
+		name string
+		s    Style
+		exp  string
+	}{
+		{
+			"curly",
+			TrueColor.String("foobar").UnderlineStyle(CurlyUnderline),
+			"\x1b[4:3mfoobar\x1b[0m",
+		},
+		{
+			"dashed",
+			ANSI256.String("foobar").UnderlineStyle(DashedUnderline),
+			"\x1b[4:5mfoobar\x1b[0m",
+		},
+		{
+			"none",
+			TrueColor.String("foobar").Underline().UnderlineStyle(NoUnderline),
+			"foobar",
+		},
+		{
+			"ansi fallback",
+			ANSI.String("foobar").UnderlineStyle(DoubleUnderline),
+			"\x1b[4mfoobar\x1b[0m",
+		},
+		{
+			"double",
+			TrueColor.String("foobar").DoubleUnderline().UnderlineStyle(DoubleUnderline),
+			"\x1b[4:2mfoobar\x1b[0m",
+		},
+		{
+			"double ansi fallback",
+			ANSI.String("foobar").DoubleUnderline(),
+			"\x1b[4mfoobar\x1b[0m",
+		},
+		{
+			"unknown",
+			TrueColor.String("foobar").UnderlineStyle(9),
+			"\x1b[4mfoobar\x1b[0m",
+		},
+		{
+			"negative",
+			TrueColor.String("foobar").UnderlineStyle(-1),
+			"\x1b[4mfoobar\x1b[0m",
+		},
+		{
+			"ascii",
+			Ascii.String("foobar").UnderlineStyle(CurlyUnderline),
+			"foobar",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.s.String() != test.exp {
+				t.Errorf("Expected %q, got %q", test.exp, test.s.String())
+			}
+		})
+	}
+}
+
+func TestUnderlineColor(t *testing.T) {
+	tests := []struct {
+		name string
+		s    Style
+		exp  string
+	}{
+		{
+			"rgb",
+			TrueColor.String("foobar").UnderlineStyle(CurlyUnderline).UnderlineColor(TrueColor.Color("#ff0000")),
+			"\x1b[4:3;58;2;255;0;0mfoobar\x1b[0m",
+		},
+		{
+			"ansi256",
+			ANSI256.String("foobar").Underline().UnderlineColor(ANSI256.Color("196")),
+			"\x1b[4;58;5;196mfoobar\x1b[0m",
+		},
+		{
+			"ansi color",
+			ANSI256.String("foobar").Underline().UnderlineColor(ANSIBrightRed),
+			"\x1b[4;58;5;9mfoobar\x1b[0m",
+		},
+		{
+			"no color",
+			TrueColor.String("foobar").Underline().UnderlineColor(NoColor{}),
+			"\x1b[4mfoobar\x1b[0m",
+		},
+		{
+			"ansi fallback",
+			ANSI.String("foobar").Underline().UnderlineColor(ANSIBrightRed),
+			"\x1b[4mfoobar\x1b[0m",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.s.String() != test.exp {
+				t.Errorf("Expected %q, got %q", test.exp, test.s.String())
+			}
+		})
+	}
+}
+
+func TestNestedStyles(t *testing.T) {
+	outer := TrueColor.String().Foreground(ANSIColor(1))
+	inner := TrueColor.String().Bold()
+
+	tt := []struct {
+		Input    string
+		Expected string
+	}{
+		{
+			Input:    "a " + inner.Styled("b") + " c",
+			Expected: "\x1b[31ma \x1b[1mb\x1b[0m\x1b[31m c\x1b[0m",
+		},
+		{
+			Input:    "a " + inner.Styled("b"),
+			Expected: "\x1b[31ma \x1b[1mb\x1b[0m\x1b[0m",
+		},
+		{
+			Input:    "a\x1b[m b\x1b[1m c",
+			Expected: "\x1b[31ma\x1b[m\x1b[31m b\x1b[1m c\x1b[0m",
+		},
+		{
+			Input:    "a \x1b[2Kb",
+			Expected: "\x1b[31ma \x1b[2Kb\x1b[0m",
+		},
+	}
+
+	for i, test := range tt {
+		t.Run("", func(t *testing.T) {
+			s := outer.Styled(test.Input)
+			if s != test.Expected {
+				t.Errorf("Test %d: expected %q, got %q", i, test.Expected, s)
+			}
+		})
+	}
+}
+
+func TestStyleLastWriteWins(t *testing.T) {
+	s := TrueColor.String("foobar").
+		Foreground(ANSIRed).
+		Foreground(ANSIBlue).
+		Bold().
+		Bold()
+
+	exp := "\x1b[34;1mfoobar\x1b[0m"
+	if s.String() != exp {
+		t.Errorf("Expected %q, got %q", exp, s.String())
+	}
+
+	// nil colors, e.g. from invalid color strings, are ignored
+	s = s.Foreground(nil).Background(TrueColor.Color("invalid"))
+	if s.String() != exp {
+		t.Errorf("Expected %q, got %q", exp, s.String())
+	}
+
+	s = s.InheritForeground()
+	exp = "\x1b[1mfoobar\x1b[0m"
+	if s.String() != exp {
+		t.Errorf("Expected %q, got %q", exp, s.String())
+	}
+}
+
+func TestStyleInheritColors(t *testing.T) {
+	base := TrueColor.String("foobar").
+		Foreground(ANSIRed).
+		Background(ANSIBlue).
+		UnderlineColor(ANSIGreen)
+	s := TrueColor.String().
+		Foreground(ANSIYellow).
+		Background(NoColor{}).
+		UnderlineColor(ANSIWhite).
+		InheritForeground().
+		InheritBackground().
+		InheritUnderlineColor()
+
+	if m := base.Merge(s); m != base {
+		t.Errorf("Expected %+v, got %+v", base, m)
+	}
+}
+
+func TestStyleGetters(t *testing.T) {
+	s := TrueColor.String().
+		Foreground(ANSIRed).
+		Background(ANSI256Color(69)).
+		UnderlineStyle(CurlyUnderline).
+		UnderlineColor(RGBColor("#ff0000")).
+		Bold().
+		Italic()
+
+	if s.GetForeground() != ANSIRed {
+		t.Errorf("Expected foreground %v, got %v", ANSIRed, s.GetForeground())
+	}
+	if s.GetBackground() != ANSI256Color(69) {
+		t.Errorf("Expected background %v, got %v", ANSI256Color(69), s.GetBackground())
+	}
+	if s.GetUnderlineColor() != RGBColor("#ff0000") {
+		t.Errorf("Expected underline color %v, got %v", RGBColor("#ff0000"), s.GetUnderlineColor())
+	}
+	if s.GetUnderlineStyle() != CurlyUnderline {
+		t.Errorf("Expected underline style %d, got %d", CurlyUnderline, s.GetUnderlineStyle())
+	}
+	if !s.Has(BoldAttribute | ItalicAttribute) {
+		t.Errorf("Expected style to be bold and italic")
+	}
+	if s.Has(BoldAttribute | FaintAttribute) {
+		t.Errorf("Expected style not to be faint")
+	}
+
+	exp := BoldAttribute | ItalicAttribute | UnderlineAttribute
+	if s.Attributes() != exp {
+		t.Errorf("Expected attributes %b, got %b", exp, s.Attributes())
+	}
+
+	s = s.UnderlineStyle(NoUnderline)
+	if s.GetUnderlineStyle() != NoUnderline {
+		t.Errorf("Expected underline style %d, got %d", NoUnderline, s.GetUnderlineStyle())
+	}
+	if !s.IsUnset(UnderlineAttribute) {
+		t.Errorf("Expected underline to be unset")
+	}
+}
+
+func TestStyleMerge(t *testing.T) {
+	base := TrueColor.String("foobar").
+		Foreground(ANSIRed).
+		Background(ANSIBlue).
+		Bold().
+		Italic()
+
+	tt := []struct {
+		Other    Style
+		Expected string
+	}{
+		{
+			Other:    Style{},
+			Expected: "\x1b[31;44;1;3mfoobar\x1b[0m",
+		},
+		{
+			Other:    TrueColor.String().Foreground(ANSIGreen).Underline(),
+			Expected: "\x1b[32;44;1;3;4mfoobar\x1b[0m",
+		},
+		{
+			Other:    TrueColor.String().Background(NoColor{}).Unset(BoldAttribute),
+			Expected: "\x1b[31;3mfoobar\x1b[0m",
+		},
+		{
+			Other:    TrueColor.String().Unset(BoldAttribute).Inherit(BoldAttribute),
+			Expected: "\x1b[31;44;1;3mfoobar\x1b[0m",
+		},
+	}
+
+	for i, test := range tt {
+		t.Run("", func(t *testing.T) {
+			s := base.Merge(test.Other)
+			if s.String() != test.Expected {
+				t.Errorf("Test %d: expected %q, got %q", i, test.Expected, s.String())
+			}
+		})
+	}
+}
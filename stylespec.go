@@ -0,0 +1,257 @@
+package termenv
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidStyle gets returned when a style spec is invalid.
+var ErrInvalidStyle = errors.New("invalid style")
+
+// attributeNames maps the names used in style specs to attributes. The first
+// name listed for an attribute is the one Spec uses.
+var attributeNames = []struct {
+	attr  Attribute
+	names []string
+}{
+	{BoldAttribute, []string{"bold"}},
+	{FaintAttribute, []string{"faint", "dim"}},
+	{ItalicAttribute, []string{"italic"}},
+	{UnderlineAttribute, []string{"underline", "ul"}},
+	{BlinkAttribute, []string{"blink"}},
+	{RapidBlinkAttribute, []string{"rapid-blink"}},
+	{ReverseAttribute, []string{"reverse"}},
+	{ConcealAttribute, []string{"conceal", "hidden"}},
+	{CrossOutAttribute, []string{"strike", "crossout"}},
+	{OverlineAttribute, []string{"overline"}},
+	{SuperscriptAttribute, []string{"superscript"}},
+	{SubscriptAttribute, []string{"subscript"}},
+}
+
+// underlineStyleNames are the names of underline styles, as used in specs
+// like "underline:curly".
+var underlineStyleNames = map[UnderlineStyle]string{
+	SingleUnderline: "single",
+	DoubleUnderline: "double",
+	CurlyUnderline:  "curly",
+	DottedUnderline: "dotted",
+	DashedUnderline: "dashed",
+}
+
+// ParseStyle parses a textual style spec, such as "bold italic #ff8800 on 236"
+// or "red blue ul", as commonly found in configuration files.
+//
+// A spec is a whitespace-separated list of attributes and colors:
+//
+//   - Attributes are bold, faint (dim), italic, underline (ul), blink,
+//     rapid-blink, reverse, conceal (hidden), strike (crossout), overline,
+//     superscript and subscript. Prefixing an attribute with "no" or "no-"
+//     unsets it, e.g. "nobold".
+//   - The underline style can be chosen with "underline:curly", where the
+//     style is one of single, double, curly, dotted or dashed.
+//     "double-underline" is a shorthand for "underline:double".
+//   - The first color is the foreground color, the second one the background
+//     color. "on <color>" sets the background color explicitly, and
+//     "underline-color <color>" sets the underline color.
+//   - Colors are anything understood by ParseColor, as long as it doesn't
+//     contain spaces, e.g. "#ff8800", "236", "brightblue" or "rgb(1,2,3)".
+//     "default" unsets a color, while "normal" leaves it untouched.
+//
+// Like String, ParseStyle returns a Style for the ANSI profile. Colors are
+// kept as they are, use Profile.ParseStyle to convert them.
+func ParseStyle(spec string) (Style, error) {
+	s, err := TrueColor.ParseStyle(spec)
+	s.profile = ANSI
+	return s, err
+}
+
+// ParseStyle parses a textual style spec, converting all colors to the
+// Profile. See the package-level ParseStyle for the syntax of specs.
+func (p Profile) ParseStyle(spec string) (Style, error) {
+	return parseStyle(p.String(), spec)
+}
+
+// ParseStyle parses a textual style spec, converting all colors like
+// Output.Convert does, and returns a Style for the Output like Output.String.
+// See the package-level ParseStyle for the syntax of specs.
+func (o *Output) ParseStyle(spec string) (Style, error) {
+	return parseStyle(o.String(), spec)
+}
+
+// parseStyle parses a style spec on top of s, converting colors like s does.
+func parseStyle(s Style, spec string) (Style, error) {
+	fields := strings.Fields(spec)
+
+	var fgSeen, bgSeen bool
+	for i := 0; i < len(fields); i++ {
+		w := strings.ToLower(fields[i])
+
+		switch w {
+		case "on", "underline-color":
+			if i+1 == len(fields) {
+				return Style{}, fmt.Errorf("%w: missing color after %q", ErrInvalidStyle, w)
+			}
+			i++
+			c, err := parseSpecColor(s, fields[i])
+			if err != nil {
+				return Style{}, err
+			}
+
+			if w == "underline-color" {
+				s.ul = c
+				continue
+			}
+			if bgSeen {
+				return Style{}, fmt.Errorf("%w: more than one background color", ErrInvalidStyle)
+			}
+			s.bg = c
+			bgSeen = true
+			continue
+		}
+
+		if w == "double-underline" {
+			s = s.DoubleUnderline()
+			continue
+		}
+
+		if a, ok := attributeByName(w); ok {
+			if a == UnderlineAttribute {
+				s = s.Underline()
+				continue
+			}
+			s = s.set(a)
+			continue
+		}
+
+		if strings.HasPrefix(w, "underline:") || strings.HasPrefix(w, "ul:") {
+			u, ok := underlineStyleByName(w[strings.Index(w, ":")+1:])
+			if !ok {
+				return Style{}, fmt.Errorf("%w: unknown underline style %q", ErrInvalidStyle, fields[i])
+			}
+			s = s.UnderlineStyle(u)
+			continue
+		}
+
+		if strings.HasPrefix(w, "no") {
+			if a, ok := attributeByName(strings.TrimPrefix(w[2:], "-")); ok {
+				s = s.Unset(a)
+				continue
+			}
+		}
+
+		c, err := parseSpecColor(s, fields[i])
+		if err != nil {
+			return Style{}, err
+		}
+		switch {
+		case !fgSeen:
+			s.fg = c
+			fgSeen = true
+		case !bgSeen:
+			s.bg = c
+			bgSeen = true
+		default:
+			return Style{}, fmt.Errorf("%w: too many colors in %q", ErrInvalidStyle, spec)
+		}
+	}
+
+	return s, nil
+}
+
+// parseSpecColor parses a color of a style spec and converts it like t does.
+// It returns nil for "normal", which leaves the color untouched.
+func parseSpecColor(t Style, s string) (Color, error) {
+	switch strings.ToLower(s) {
+	case "normal":
+		return nil, nil
+	case "default":
+		return NoColor{}, nil
+	}
+
+	c, err := ParseColor(s)
+	if err != nil {
+		if strings.ContainsAny(s[:1], "#-0123456789") || strings.ContainsAny(s, "(:") {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: unknown attribute or color %q", ErrInvalidStyle, s)
+	}
+	return t.convert(c), nil
+}
+
+// Spec returns the textual spec of the style, which can be parsed again with
+// ParseStyle.
+//
+// Specs can't express adaptive and complete colors, so Spec is lossy for
+// them: it writes the color they currently render as instead.
+func (t Style) Spec() string {
+	var words []string
+	for _, a := range attributeNames {
+		switch {
+		case t.attrs&a.attr != 0:
+			if a.attr == UnderlineAttribute && t.underline != SingleUnderline {
+				words = append(words, "underline:"+underlineStyleNames[t.underline])
+				continue
+			}
+			words = append(words, a.names[0])
+		case t.unset&a.attr != 0:
+			words = append(words, "no-"+a.names[0])
+		}
+	}
+
+	dark := true
+	if t.hasAdaptiveColor() {
+		dark = t.darkBackground()
+	}
+
+	if t.fg != nil {
+		words = append(words, specColor(t.color(t.fg, dark)))
+	}
+	if t.bg != nil {
+		words = append(words, "on", specColor(t.color(t.bg, dark)))
+	}
+	if t.ul != nil {
+		words = append(words, "underline-color", specColor(t.color(t.ul, dark)))
+	}
+
+	return strings.Join(words, " ")
+}
+
+// specColor returns the name of c, as used in style specs.
+func specColor(c Color) string {
+	switch v := c.(type) {
+	case ANSIColor:
+		if v >= 0 && int(v) < len(ansiNames) {
+			return ansiNames[v]
+		}
+		return strconv.Itoa(int(v))
+	case ANSI256Color:
+		return strconv.Itoa(int(v))
+	case RGBColor:
+		return string(v)
+	case RGB:
+		return v.String()
+	}
+	return "default"
+}
+
+func attributeByName(name string) (Attribute, bool) {
+	for _, a := range attributeNames {
+		for _, n := range a.names {
+			if n == name {
+				return a.attr, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func underlineStyleByName(name string) (UnderlineStyle, bool) {
+	for u, n := range underlineStyleNames {
+		if n == name {
+			return u, true
+		}
+	}
+	return NoUnderline, false
+}
@@ -0,0 +1,163 @@
+package termenv
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestParseStyle(t *testing.T) {
+	tt := []struct {
+		Spec     string
+		Expected Style
+		Out      string
+	}{
+		{
+			Spec:     "",
+			Expected: String(),
+			Out:      "",
+		},
+		{
+			Spec:     "bold italic #ff8800 on 236",
+			Expected: String().Bold().Italic().Foreground(RGBColor("#ff8800")).Background(ANSI256Color(236)),
+			Out:      "bold italic #ff8800 on 236",
+		},
+		{
+			Spec:     "red blue ul",
+			Expected: String().Foreground(ANSIRed).Background(ANSIBlue).Underline(),
+			Out:      "underline red on blue",
+		},
+		{
+			Spec:     "normal BrightRed dim",
+			Expected: String().Background(ANSIBrightRed).Faint(),
+			Out:      "faint on brightred",
+		},
+		{
+			Spec:     "nobold no-italic default",
+			Expected: String().Unset(BoldAttribute | ItalicAttribute).Foreground(NoColor{}),
+			Out:      "no-bold no-italic default",
+		},
+		{
+			Spec:     "underline:curly underline-color #ff0000 strike",
+			Expected: String().UnderlineStyle(CurlyUnderline).UnderlineColor(RGBColor("#ff0000")).CrossOut(),
+			Out:      "underline:curly strike underline-color #ff0000",
+		},
+		{
+			Spec:     "double-underline underline:double",
+			Expected: String().DoubleUnderline(),
+			Out:      "underline:double",
+		},
+		{
+			Spec:     "on 7 green",
+			Expected: String().Background(ANSIWhite).Foreground(ANSIGreen),
+			Out:      "green on white",
+		},
+	}
+
+	for _, test := range tt {
+		t.Run(test.Spec, func(t *testing.T) {
+			s, err := ParseStyle(test.Spec)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s != test.Expected {
+				t.Errorf("Expected %+v, got %+v", test.Expected, s)
+			}
+			if s.Spec() != test.Out {
+				t.Errorf("Expected spec %q, got %q", test.Out, s.Spec())
+			}
+
+			r, err := ParseStyle(s.Spec())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if r != s {
+				t.Errorf("Spec %q did not round-trip: expected %+v, got %+v", s.Spec(), s, r)
+			}
+		})
+	}
+}
+
+func TestParseStyleProfile(t *testing.T) {
+	s, err := ANSI256.ParseStyle("bold #ff0000")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exp := "\x1b[38;5;196;1mfoobar\x1b[0m"
+	if s.Styled("foobar") != exp {
+		t.Errorf("Expected %q, got %q", exp, s.Styled("foobar"))
+	}
+}
+
+func TestParseStyleOutput(t *testing.T) {
+	pal := DefaultPalette()
+	pal[ANSIRed] = RGBColor("#ff8700")
+	o := NewOutput(io.Discard, WithProfile(ANSI), WithPalette(pal))
+
+	s, err := o.ParseStyle("#ff8800")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.GetForeground() != o.Color("#ff8800") {
+		t.Errorf("Expected %v, got %v", o.Color("#ff8800"), s.GetForeground())
+	}
+
+	exp := "\x1b[31mfoobar\x1b[0m"
+	if s.Styled("foobar") != exp {
+		t.Errorf("Expected %q, got %q", exp, s.Styled("foobar"))
+	}
+
+	// unmarshaling converts colors like the output, too
+	s = o.String("foobar")
+	if err := s.UnmarshalText([]byte("#ff8800")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.String() != exp {
+		t.Errorf("Expected %q, got %q", exp, s.String())
+	}
+}
+
+func TestSpecAdaptiveColors(t *testing.T) {
+	tt := []struct {
+		Style    Style
+		Expected string
+	}{
+		{TrueColor.String().Foreground(AdaptiveColor{Light: ANSIBlack, Dark: ANSIWhite}), "white"},
+		{ANSI.String().Foreground(CompleteColor{TrueColor: RGBColor("#ff0000"), ANSI: ANSIBlue}), "blue"},
+		{TrueColor.String().Background(CompleteColor{TrueColor: RGBColor("#ff0000"), ANSI: ANSIBlue}), "on #ff0000"},
+	}
+
+	for _, test := range tt {
+		t.Run(test.Expected, func(t *testing.T) {
+			if s := test.Style.Spec(); s != test.Expected {
+				t.Errorf("Expected %q, got %q", test.Expected, s)
+			}
+		})
+	}
+}
+
+func TestParseStyleErrors(t *testing.T) {
+	tt := []struct {
+		Spec string
+		Err  error
+	}{
+		{"bold frobnicate", ErrInvalidStyle},
+		{"red on", ErrInvalidStyle},
+		{"red green blue", ErrInvalidStyle},
+		{"red on green on blue", ErrInvalidStyle},
+		{"underline:wavy", ErrInvalidStyle},
+		{"#ff00zz", ErrInvalidColor},
+		{"256", ErrInvalidColor},
+		{"on -1", ErrInvalidColor},
+	}
+
+	for _, test := range tt {
+		t.Run(test.Spec, func(t *testing.T) {
+			_, err := ParseStyle(test.Spec)
+			if !errors.Is(err, test.Err) {
+				t.Errorf("Expected error %v, got %v", test.Err, err)
+			}
+		})
+	}
+}
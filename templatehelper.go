@@ -47,14 +47,19 @@ func TemplateFuncs(p Profile) template.FuncMap {
 
 			return s.String()
 		},
-		"Bold":      styleFunc(p, Style.Bold),
-		"Faint":     styleFunc(p, Style.Faint),
-		"Italic":    styleFunc(p, Style.Italic),
-		"Underline": styleFunc(p, Style.Underline),
-		"Overline":  styleFunc(p, Style.Overline),
-		"Blink":     styleFunc(p, Style.Blink),
-		"Reverse":   styleFunc(p, Style.Reverse),
-		"CrossOut":  styleFunc(p, Style.CrossOut),
+		"Bold":            styleFunc(p, Style.Bold),
+		"Faint":           styleFunc(p, Style.Faint),
+		"Italic":          styleFunc(p, Style.Italic),
+		"Underline":       styleFunc(p, Style.Underline),
+		"DoubleUnderline": styleFunc(p, Style.DoubleUnderline),
+		"Overline":        styleFunc(p, Style.Overline),
+		"Blink":           styleFunc(p, Style.Blink),
+		"RapidBlink":      styleFunc(p, Style.RapidBlink),
+		"Reverse":         styleFunc(p, Style.Reverse),
+		"Conceal":         styleFunc(p, Style.Conceal),
+		"CrossOut":        styleFunc(p, Style.CrossOut),
+		"Superscript":     styleFunc(p, Style.Superscript),
+		"Subscript":       styleFunc(p, Style.Subscript),
 	}
 }
 
@@ -66,17 +71,22 @@ func styleFunc(p Profile, f func(Style) Style) func(...interface{}) string {
 }
 
 var noopTemplateFuncs = template.FuncMap{
-	"Color":      noColorFunc,
-	"Foreground": noColorFunc,
-	"Background": noColorFunc,
-	"Bold":       noStyleFunc,
-	"Faint":      noStyleFunc,
-	"Italic":     noStyleFunc,
-	"Underline":  noStyleFunc,
-	"Overline":   noStyleFunc,
-	"Blink":      noStyleFunc,
-	"Reverse":    noStyleFunc,
-	"CrossOut":   noStyleFunc,
+	"Color":           noColorFunc,
+	"Foreground":      noColorFunc,
+	"Background":      noColorFunc,
+	"Bold":            noStyleFunc,
+	"Faint":           noStyleFunc,
+	"Italic":          noStyleFunc,
+	"Underline":       noStyleFunc,
+	"DoubleUnderline": noStyleFunc,
+	"Overline":        noStyleFunc,
+	"Blink":           noStyleFunc,
+	"RapidBlink":      noStyleFunc,
+	"Reverse":         noStyleFunc,
+	"Conceal":         noStyleFunc,
+	"CrossOut":        noStyleFunc,
+	"Superscript":     noStyleFunc,
+	"Subscript":       noStyleFunc,
 }
 
 func noColorFunc(values ...interface{}) string {
@@ -0,0 +1,71 @@
+package termenv
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+)
+
+// Capabilities queries the terminal for the given termcap/terminfo
+// capabilities, e.g. "Tc", "RGB", "Smulx" or "Ss", using XTGETTCAP. It
+// returns the capabilities supported by the terminal, mapped to their values.
+// Boolean capabilities map to an empty string.
+func (o *Output) Capabilities(names ...string) (map[string]string, error) {
+	var req strings.Builder
+	for _, name := range names {
+		req.WriteString(DCS + "+q" + hex.EncodeToString([]byte(name)) + ST)
+	}
+
+	replies, err := o.Query(context.Background(), req.String(), func(r Reply) bool {
+		return r.Type == DCSReply && strings.HasPrefix(r.Payload, "1+r")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	caps := make(map[string]string, len(replies))
+	for _, r := range replies {
+		name, value, ok := parseTermcapReply(r.Payload)
+		if ok {
+			caps[name] = value
+		}
+	}
+
+	return caps, nil
+}
+
+// parseTermcapReply parses the reply to an XTGETTCAP request:
+// "1+r<hex name>=<hex value>". Both name and value are hex-encoded.
+func parseTermcapReply(s string) (name, value string, ok bool) {
+	if !strings.HasPrefix(s, "1+r") {
+		return "", "", false
+	}
+
+	kv := strings.SplitN(strings.TrimPrefix(s, "1+r"), "=", 2) //nolint:mnd
+	n, err := hex.DecodeString(kv[0])
+	if err != nil || len(n) == 0 {
+		return "", "", false
+	}
+	if len(kv) == 1 {
+		return string(n), "", true
+	}
+
+	v, err := hex.DecodeString(kv[1])
+	if err != nil {
+		return "", "", false
+	}
+	return string(n), string(v), true
+}
+
+// hasTrueColorCapability reports whether the terminal confirms TrueColor
+// support via the RGB or Tc capabilities.
+func (o *Output) hasTrueColorCapability() bool {
+	caps, err := o.Capabilities("RGB", "Tc")
+	if err != nil {
+		return false
+	}
+
+	_, rgb := caps["RGB"]
+	_, tc := caps["Tc"]
+	return rgb || tc
+}
@@ -0,0 +1,32 @@
+package termenv
+
+import "testing"
+
+func TestParseTermcapReply(t *testing.T) {
+	tests := []struct {
+		input string
+		name  string
+		value string
+		valid bool
+	}{
+		{"1+r5463", "Tc", "", true},
+		{"1+r636f6c6f7273=323536", "colors", "256", true},
+		{"1+r536d756c78=1b5b343a25703125646d", "Smulx", "\x1b[4:%p1%dm", true},
+		{"0+r5463", "", "", false},
+		{"1+r", "", "", false},
+		{"1+rzz", "", "", false},
+		{"1+r5463=zz", "", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			name, value, ok := parseTermcapReply(test.input)
+			if ok != test.valid {
+				t.Fatalf("Expected valid to be %t, got %t", test.valid, ok)
+			}
+			if name != test.name || value != test.value {
+				t.Errorf("Expected %q=%q, got %q=%q", test.name, test.value, name, value)
+			}
+		})
+	}
+}
@@ -21,6 +21,10 @@ const (
 	CSI = string(ESC) + "["
 	// Operating System Command.
 	OSC = string(ESC) + "]"
+	// Device Control String.
+	DCS = string(ESC) + "P"
+	// Application Program Command.
+	APC = string(ESC) + "_"
 	// String Terminator.
 	ST = string(ESC) + `\`
 )
@@ -3,7 +3,10 @@
 
 package termenv
 
-import "io"
+import (
+	"context"
+	"io"
+)
 
 // ColorProfile returns the supported color profile:
 // ANSI256
@@ -21,6 +24,17 @@ func (o Output) backgroundColor() Color {
 	return ANSIColor(0)
 }
 
+func (o Output) disableEcho(_ File) (func(), error) {
+	if o.unsafe {
+		return func() {}, nil
+	}
+	return nil, ErrStatusReport
+}
+
+func (o *Output) waitForData(_ context.Context, _ File) error {
+	return ErrStatusReport
+}
+
 // EnableVirtualTerminalProcessing enables virtual terminal processing on
 // Windows for w and returns a function that restores w to its previous state.
 // On non-Windows platforms, or if w does not refer to a terminal, then it
@@ -71,7 +71,7 @@ func TestRendering(t *testing.T) {
 	out = out.Underline()
 	out = out.Blink()
 
-	exp := "\x1b[38;2;171;205;239;48;5;69;1;3;2;4;5mfoobar\x1b[0m"
+	exp := "\x1b[38;2;171;205;239;48;5;69;1;2;3;4;5mfoobar\x1b[0m"
 	if out.String() != exp {
 		t.Errorf("Expected %s, got %s", exp, out.String())
 	}
@@ -274,6 +274,26 @@ func TestTemplateHelpers(t *testing.T) {
 			Template: fmt.Sprintf(basetpl, "CrossOut"),
 			Expected: exp.CrossOut().String(),
 		},
+		{
+			Template: fmt.Sprintf(basetpl, "DoubleUnderline"),
+			Expected: p.String("Hello World").DoubleUnderline().String(),
+		},
+		{
+			Template: fmt.Sprintf(basetpl, "RapidBlink"),
+			Expected: exp.RapidBlink().String(),
+		},
+		{
+			Template: fmt.Sprintf(basetpl, "Conceal"),
+			Expected: exp.Conceal().String(),
+		},
+		{
+			Template: fmt.Sprintf(basetpl, "Superscript"),
+			Expected: exp.Superscript().String(),
+		},
+		{
+			Template: fmt.Sprintf(basetpl, "Subscript"),
+			Expected: exp.Subscript().String(),
+		},
 		{
 			Template: fmt.Sprintf(wraptpl, "Underline", "Bold"),
 			Expected: String(exp.Bold().String()).Underline().String(),
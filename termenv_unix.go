@@ -4,6 +4,7 @@
 package termenv
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"strconv"
@@ -13,10 +14,9 @@ import (
 	"golang.org/x/sys/unix"
 )
 
-const (
-	// timeout for OSC queries.
-	OSCTimeout = 5 * time.Second
-)
+// queryPollInterval is how often we check for cancellation while waiting
+// for the terminal to reply.
+const queryPollInterval = 100 * time.Millisecond
 
 // ColorProfile returns the supported color profile:
 // Ascii, ANSI, ANSI256, or TrueColor.
@@ -25,6 +25,18 @@ func (o *Output) ColorProfile() Profile {
 		return Ascii
 	}
 
+	p := o.termColorProfile()
+
+	// only upgrade terminals that support colors, and don't override the
+	// ANSI256 downgrade for screen, which can't render TrueColor
+	if (p == ANSI || p == ANSI256) && o.termcap && o.canQueryOSC() && o.hasTrueColorCapability() {
+		return TrueColor
+	}
+	return p
+}
+
+// termColorProfile returns the color profile based on environment variables.
+func (o *Output) termColorProfile() Profile {
 	if o.environ.Getenv("GOOGLE_CLOUD_SHELL") == "true" {
 		return TrueColor
 	}
@@ -62,6 +74,11 @@ func (o *Output) ColorProfile() Profile {
 		return ANSI
 	}
 
+	// let the terminfo database tell us what the terminal supports
+	if ti, err := o.Terminfo(); err == nil {
+		return ti.ColorProfile()
+	}
+
 	if strings.Contains(term, "256color") {
 		return ANSI256
 	}
@@ -121,175 +138,90 @@ func (o Output) backgroundColor() Color {
 	return ANSIColor(0)
 }
 
-func (o *Output) waitForData(timeout time.Duration) error {
-	fd := o.TTY().Fd()
-	tv := unix.NsecToTimeval(int64(timeout))
-	var readfds unix.FdSet
-	readfds.Set(int(fd)) //nolint:gosec
+// waitForData waits until the terminal has data for us to read, or until ctx
+// is done.
+func (o *Output) waitForData(ctx context.Context, tty File) error {
+	fd := int(tty.Fd()) //nolint:gosec
 
 	for {
-		n, err := unix.Select(int(fd)+1, &readfds, nil, nil, &tv) //nolint:gosec
-		if err == unix.EINTR {
-			continue
-		}
-		if err != nil {
+		if err := ctx.Err(); err != nil {
 			return err //nolint:wrapcheck
 		}
-		if n == 0 {
-			return fmt.Errorf("timeout")
-		}
-
-		break
-	}
-
-	return nil
-}
 
-func (o *Output) readNextByte() (byte, error) {
-	if !o.unsafe {
-		if err := o.waitForData(OSCTimeout); err != nil {
-			return 0, err
+		// wake up regularly to notice cancellation of ctx
+		timeout := queryPollInterval
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < timeout {
+			timeout = time.Until(deadline)
 		}
-	}
-
-	var b [1]byte
-	n, err := o.TTY().Read(b[:])
-	if err != nil {
-		return 0, err //nolint:wrapcheck
-	}
-
-	if n == 0 {
-		panic("read returned no data")
-	}
-
-	return b[0], nil
-}
+		tv := unix.NsecToTimeval(int64(timeout))
 
-// readNextResponse reads either an OSC response or a cursor position response:
-//   - OSC response: "\x1b]11;rgb:1111/1111/1111\x1b\\"
-//   - cursor position response: "\x1b[42;1R"
-func (o *Output) readNextResponse() (response string, isOSC bool, err error) {
-	start, err := o.readNextByte()
-	if err != nil {
-		return "", false, err
-	}
+		var readfds unix.FdSet
+		readfds.Set(fd)
 
-	// first byte must be ESC
-	for start != ESC {
-		start, err = o.readNextByte()
-		if err != nil {
-			return "", false, err
+		n, err := unix.Select(fd+1, &readfds, nil, nil, &tv)
+		if err == unix.EINTR {
+			continue
 		}
-	}
-
-	response += string(start)
-
-	// next byte is either '[' (cursor position response) or ']' (OSC response)
-	tpe, err := o.readNextByte()
-	if err != nil {
-		return "", false, err
-	}
-
-	response += string(tpe)
-
-	var oscResponse bool
-	switch tpe {
-	case '[':
-		oscResponse = false
-	case ']':
-		oscResponse = true
-	default:
-		return "", false, ErrStatusReport
-	}
-
-	for {
-		b, err := o.readNextByte()
 		if err != nil {
-			return "", false, err
-		}
-
-		response += string(b)
-
-		if oscResponse {
-			// OSC can be terminated by BEL (\a) or ST (ESC)
-			if b == BEL || strings.HasSuffix(response, string(ESC)) {
-				return response, true, nil
-			}
-		} else {
-			// cursor position response is terminated by 'R'
-			if b == 'R' {
-				return response, false, nil
-			}
+			return fmt.Errorf("%s: %s", ErrStatusReport, err)
 		}
-
-		// both responses have less than 25 bytes, so if we read more, that's an error
-		if len(response) > 25 { //nolint:mnd
-			break
+		if n > 0 {
+			return nil
 		}
 	}
-
-	return "", false, ErrStatusReport
 }
 
 func (o Output) termStatusReport(sequence int) (string, error) {
-	// screen/tmux can't support OSC, because they can be connected to multiple
-	// terminals concurrently.
-	term := o.environ.Getenv("TERM")
-	if strings.HasPrefix(term, "screen") || strings.HasPrefix(term, "tmux") || strings.HasPrefix(term, "dumb") {
+	if !o.canQueryOSC() {
 		return "", ErrStatusReport
 	}
 
-	tty := o.TTY()
-	if tty == nil {
-		return "", ErrStatusReport
+	prefix := fmt.Sprintf("%d;", sequence)
+	replies, err := o.Query(context.Background(), fmt.Sprintf(OSC+"%d;?"+ST, sequence), func(r Reply) bool {
+		return r.Type == OSCReply && strings.HasPrefix(r.Payload, prefix)
+	})
+	if err != nil {
+		return "", err
 	}
 
-	if !o.unsafe {
-		fd := int(tty.Fd()) //nolint:gosec
-		// if in background, we can't control the terminal
-		if !isForeground(fd) {
-			return "", ErrStatusReport
-		}
-
-		t, err := unix.IoctlGetTermios(fd, tcgetattr)
-		if err != nil {
-			return "", fmt.Errorf("%s: %s", ErrStatusReport, err)
-		}
-		defer unix.IoctlSetTermios(fd, tcsetattr, t) //nolint:errcheck
-
-		noecho := *t
-		noecho.Lflag = noecho.Lflag &^ unix.ECHO
-		noecho.Lflag = noecho.Lflag &^ unix.ICANON
-		if err := unix.IoctlSetTermios(fd, tcsetattr, &noecho); err != nil {
-			return "", fmt.Errorf("%s: %s", ErrStatusReport, err)
-		}
+	// if there is no OSC response, then the terminal does not support it
+	if len(replies) == 0 {
+		return "", ErrStatusReport
 	}
 
-	// first, send OSC query, which is ignored by terminal which do not support it
-	fmt.Fprintf(tty, OSC+"%d;?"+ST, sequence) //nolint:errcheck
-
-	// then, query cursor position, should be supported by all terminals
-	fmt.Fprintf(tty, CSI+"6n") //nolint:errcheck
+	return replies[0].String(), nil
+}
 
-	// read the next response
-	res, isOSC, err := o.readNextResponse()
-	if err != nil {
-		return "", fmt.Errorf("%s: %s", ErrStatusReport, err)
+// disableEcho puts the terminal into non-canonical mode without echo, so
+// responses to our queries can be read without being printed. It returns a
+// function restoring the previous terminal state. In unsafe mode the terminal
+// state is left untouched.
+func (o Output) disableEcho(tty File) (func(), error) {
+	if o.unsafe {
+		return func() {}, nil
 	}
 
-	// if this is not OSC response, then the terminal does not support it
-	if !isOSC {
-		return "", ErrStatusReport
+	fd := int(tty.Fd()) //nolint:gosec
+	// if in background, we can't control the terminal
+	if !isForeground(fd) {
+		return nil, ErrStatusReport
 	}
 
-	// read the cursor query response next and discard the result
-	_, _, err = o.readNextResponse()
+	t, err := unix.IoctlGetTermios(fd, tcgetattr)
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("%s: %s", ErrStatusReport, err)
+	}
+
+	noecho := *t
+	noecho.Lflag = noecho.Lflag &^ unix.ECHO
+	noecho.Lflag = noecho.Lflag &^ unix.ICANON
+	if err := unix.IoctlSetTermios(fd, tcsetattr, &noecho); err != nil {
+		return nil, fmt.Errorf("%s: %s", ErrStatusReport, err)
 	}
 
-	// fmt.Println("Rcvd", res[1:])
-	return res, nil
+	return func() {
+		unix.IoctlSetTermios(fd, tcsetattr, t) //nolint:errcheck
+	}, nil
 }
 
 // EnableVirtualTerminalProcessing enables virtual terminal processing on
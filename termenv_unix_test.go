@@ -0,0 +1,406 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris || zos
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris zos
+
+package termenv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeTTY is a terminal replaying canned responses to our queries.
+type fakeTTY struct {
+	in  *strings.Reader
+	out bytes.Buffer
+}
+
+func (f *fakeTTY) Read(p []byte) (int, error) {
+	return f.in.Read(p)
+}
+
+func (f *fakeTTY) Write(p []byte) (int, error) {
+	return f.out.Write(p)
+}
+
+func (f *fakeTTY) Fd() uintptr {
+	return 0
+}
+
+func fakeOutput(responses string, opts ...OutputOption) (*Output, *fakeTTY) {
+	tty := &fakeTTY{in: strings.NewReader(responses)}
+	opts = append([]OutputOption{WithUnsafe(), WithEnvironment(testEnv{})}, opts...)
+	return NewOutput(tty, opts...), tty
+}
+
+func TestCursorPosition(t *testing.T) {
+	o, tty := fakeOutput("\x1b[12;34R\x1b[?62;22c")
+
+	row, col, err := o.CursorPosition()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row != 12 || col != 34 {
+		t.Errorf("Expected 12;34, got %d;%d", row, col)
+	}
+	if exp := "\x1b[6n\x1b[c"; tty.out.String() != exp {
+		t.Errorf("Expected query %q, got %q", exp, tty.out.String())
+	}
+}
+
+func TestQuery(t *testing.T) {
+	o, tty := fakeOutput("\x1b]11;rgb:1212/3434/5656\a\x1b[1;1R\x1b[?62;22c")
+
+	replies, err := o.Query(context.Background(), OSC+"11;?"+ST, func(r Reply) bool {
+		return r.Type == OSCReply
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replies) != 1 {
+		t.Fatalf("Expected 1 reply, got %d", len(replies))
+	}
+	if exp := (Reply{OSCReply, "11;rgb:1212/3434/5656"}); replies[0] != exp {
+		t.Errorf("Expected %+v, got %+v", exp, replies[0])
+	}
+	if exp := "\x1b]11;?\x1b\\\x1b[c"; tty.out.String() != exp {
+		t.Errorf("Expected query %q, got %q", exp, tty.out.String())
+	}
+}
+
+func TestQueryAllReplies(t *testing.T) {
+	o, _ := fakeOutput("\x1b[1;1R\x1b[?62;22c")
+
+	replies, err := o.Query(context.Background(), CSI+"6n", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replies) != 2 {
+		t.Fatalf("Expected 2 replies, got %d", len(replies))
+	}
+	if !isPrimaryDeviceAttributes(replies[1]) {
+		t.Errorf("Expected DA1 reply, got %+v", replies[1])
+	}
+}
+
+func TestQueryDeadline(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close() //nolint:errcheck
+
+	tty := &pipeTTY{r}
+	o := NewOutput(tty, WithUnsafe(), WithEnvironment(testEnv{}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := o.Query(ctx, CSI+"6n", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline to be exceeded, got %v", err)
+	}
+}
+
+func TestForegroundColorQuery(t *testing.T) {
+	o, _ := fakeOutput("\x1b]10;rgb:ffff/8080/0000\x1b\\\x1b[?62;22c")
+
+	if c := o.ForegroundColor(); c != RGBColor("#ff8000") {
+		t.Errorf("Expected #ff8000, got %v", c)
+	}
+}
+
+func TestForegroundColorUnsupported(t *testing.T) {
+	o, _ := fakeOutput("\x1b[?62;22c")
+
+	if c := o.ForegroundColor(); c != ANSIColor(7) {
+		t.Errorf("Expected default color, got %v", c)
+	}
+}
+
+// pipeTTY is a terminal that never replies to our queries.
+type pipeTTY struct {
+	io.Reader
+}
+
+func (p *pipeTTY) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (p *pipeTTY) Fd() uintptr {
+	return 0
+}
+
+func TestPalette(t *testing.T) {
+	o, tty := fakeOutput("\x1b]4;1;rgb:cdcd/0000/0000\x1b\\\x1b]4;15;rgb:ffff/ffff/ffff\a\x1b[?62;22c")
+
+	p, err := o.Palette()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p[1] != RGBColor("#cd0000") {
+		t.Errorf("Expected #cd0000, got %v", p[1])
+	}
+	if p[15] != RGBColor("#ffffff") {
+		t.Errorf("Expected #ffffff, got %v", p[15])
+	}
+	// unreported colors keep their default value
+	if p[2] != RGBColor(ansiHex[2]) {
+		t.Errorf("Expected %v, got %v", ansiHex[2], p[2])
+	}
+
+	if !strings.HasPrefix(tty.out.String(), "\x1b]4;0;?\x1b\\\x1b]4;1;?\x1b\\") {
+		t.Errorf("Unexpected query %q", tty.out.String())
+	}
+}
+
+func TestPaletteUnsupported(t *testing.T) {
+	o, _ := fakeOutput("\x1b[?62;22c")
+
+	if _, err := o.Palette(); err == nil {
+		t.Errorf("Expected error for unsupported palette query")
+	}
+}
+
+func TestTerminalInfo(t *testing.T) {
+	o, tty := fakeOutput("\x1bP>|kitty(0.31.0)\x1b\\\x1b[>1;4000;31c\x1b[?62;c")
+
+	info, err := o.TerminalInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name != "kitty" || info.Version != "0.31.0" {
+		t.Errorf("Expected kitty 0.31.0, got %s %s", info.Name, info.Version)
+	}
+	if info.Class != 62 {
+		t.Errorf("Expected class 62, got %d", info.Class)
+	}
+	if exp := "\x1b[>q\x1b[>c\x1b[c"; tty.out.String() != exp {
+		t.Errorf("Expected query %q, got %q", exp, tty.out.String())
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	o, tty := fakeOutput("\x1bP1+r5463\x1b\\\x1bP1+r636f6c6f7273=323536\x1b\\\x1bP0+r536d756c78\x1b\\\x1b[?62c")
+
+	caps, err := o.Capabilities("Tc", "colors", "Smulx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := caps["Tc"]; !ok || v != "" {
+		t.Errorf("Expected Tc to be supported, got %q, %t", v, ok)
+	}
+	if v := caps["colors"]; v != "256" {
+		t.Errorf("Expected 256 colors, got %q", v)
+	}
+	if _, ok := caps["Smulx"]; ok {
+		t.Errorf("Expected Smulx to be unsupported")
+	}
+	if exp := "\x1bP+q5463\x1b\\\x1bP+q636f6c6f7273\x1b\\\x1bP+q536d756c78\x1b\\\x1b[c"; tty.out.String() != exp {
+		t.Errorf("Expected query %q, got %q", exp, tty.out.String())
+	}
+}
+
+func TestTermcapColorProfile(t *testing.T) {
+	o, _ := fakeOutput("\x1bP1+r524742\x1b\\\x1b[?62c", WithTermcapQuery(true))
+	if o.Profile != TrueColor {
+		t.Errorf("Expected %s, got %s", TrueColor.Name(), o.Profile.Name())
+	}
+
+	o, _ = fakeOutput("\x1b[?62c", WithTermcapQuery(true))
+	if o.Profile != ANSI256 {
+		t.Errorf("Expected %s, got %s", ANSI256.Name(), o.Profile.Name())
+	}
+
+	// terminals without colors, and multiplexers, don't get upgraded
+	tests := []struct {
+		env     mapEnv
+		profile Profile
+	}{
+		{mapEnv{"TERM": "dumb"}, Ascii},
+		{mapEnv{"TERM": "screen", "COLORTERM": "truecolor"}, ANSI256},
+		{mapEnv{"TERM": "screen-256color"}, ANSI256},
+		{mapEnv{"TERM": "tmux-256color"}, ANSI256},
+	}
+
+	for _, test := range tests {
+		t.Run(test.env["TERM"], func(t *testing.T) {
+			o, tty := fakeOutput("\x1bP1+r524742\x1b\\\x1b[?62c", WithEnvironment(test.env), WithTermcapQuery(true))
+			if o.Profile != test.profile {
+				t.Errorf("Expected %s, got %s", test.profile.Name(), o.Profile.Name())
+			}
+			if tty.out.Len() != 0 {
+				t.Errorf("Expected no query, got %q", tty.out.String())
+			}
+		})
+	}
+}
+
+func TestTerminfoColorProfile(t *testing.T) {
+	tests := []struct {
+		term    string
+		profile Profile
+	}{
+		{"xterm-direct", TrueColor},
+		{"termenv-tc", TrueColor},
+		{"xterm-256color", ANSI256},
+		// hardcoded profiles take precedence
+		{"xterm", ANSI},
+	}
+
+	for _, test := range tests {
+		t.Run(test.term, func(t *testing.T) {
+			env := mapEnv{"TERM": test.term, "TERMINFO": "./testdata/terminfo"}
+			o := NewOutput(io.Discard, WithEnvironment(env), WithTTY(true))
+			if o.Profile != test.profile {
+				t.Errorf("Expected %s, got %s", test.profile.Name(), o.Profile.Name())
+			}
+		})
+	}
+}
+
+func TestQueryMode(t *testing.T) {
+	o, tty := fakeOutput("\x1b[?2026;2$y\x1b[?62c")
+
+	m, err := o.QueryMode(SynchronizedOutputMode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m != ModeReset {
+		t.Errorf("Expected %s, got %s", ModeReset, m)
+	}
+	if exp := "\x1b[?2026$p\x1b[c"; tty.out.String() != exp {
+		t.Errorf("Expected query %q, got %q", exp, tty.out.String())
+	}
+
+	// terminal does not support DECRQM
+	o, _ = fakeOutput("\x1b[?62c")
+	if _, err := o.QueryMode(SynchronizedOutputMode); err == nil {
+		t.Errorf("Expected error for unsupported DECRQM")
+	}
+}
+
+func TestAdaptiveColorLightBackground(t *testing.T) {
+	o, _ := fakeOutput("\x1b]11;rgb:ffff/ffff/ffff\x1b\\\x1b[?62;22c", WithProfile(TrueColor))
+
+	c := AdaptiveColor{
+		Light: ANSIBlack,
+		Dark:  ANSIWhite,
+	}
+	if v := o.Convert(c); v != ANSIBlack {
+		t.Errorf("Expected %#v, got %#v", ANSIBlack, v)
+	}
+
+}
+
+func TestAdaptiveColorStyle(t *testing.T) {
+	c := AdaptiveColor{
+		Light: ANSIBlack,
+		Dark:  ANSIWhite,
+	}
+
+	// styles use the cached background color of their output
+	o, tty := fakeOutput("\x1b]10;rgb:0000/0000/0000\x1b\\\x1b[?62;22c"+
+		"\x1b]11;rgb:ffff/ffff/ffff\x1b\\\x1b[?62;22c"+
+		"\x1b[?62;22c", WithProfile(TrueColor), WithColorCache(true))
+	tty.out.Reset()
+
+	s := o.String("foobar").Foreground(c).Background(c).UnderlineColor(c)
+	exp := "\x1b[30;40;58;5;0mfoobar\x1b[0m"
+	for i := 0; i < 2; i++ {
+		if v := s.String(); v != exp {
+			t.Errorf("Expected %q, got %q", exp, v)
+		}
+	}
+	if tty.out.Len() != 0 {
+		t.Errorf("Expected no query, got %q", tty.out.String())
+	}
+
+	// rendering never queries the terminal, so without a cached background
+	// color the dark color gets used
+	o, tty = fakeOutput("\x1b]11;rgb:ffff/ffff/ffff\x1b\\\x1b[?62;22c", WithProfile(TrueColor))
+
+	s = o.String("foobar").Foreground(c).Background(c).UnderlineColor(c)
+	exp = "\x1b[37;47;58;5;7mfoobar\x1b[0m"
+	for i := 0; i < 2; i++ {
+		if v := s.String(); v != exp {
+			t.Errorf("Expected %q, got %q", exp, v)
+		}
+	}
+	if tty.out.Len() != 0 {
+		t.Errorf("Expected no query, got %q", tty.out.String())
+	}
+}
+
+func TestColorScheme(t *testing.T) {
+	o, tty := fakeOutput("\x1b[?997;2n\x1b[?62;22c")
+
+	c, err := o.ColorScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c != LightColorScheme {
+		t.Errorf("Expected %s, got %s", LightColorScheme, c)
+	}
+
+	exp := "\x1b[?996n\x1b[c"
+	if tty.out.String() != exp {
+		t.Errorf("Expected %q, got %q", exp, tty.out.String())
+	}
+}
+
+func TestColorSchemeUnsupported(t *testing.T) {
+	o, _ := fakeOutput("\x1b[?62;22c")
+
+	if _, err := o.ColorScheme(); err != ErrStatusReport {
+		t.Errorf("Expected %v, got %v", ErrStatusReport, err)
+	}
+}
+
+func TestColorSchemeHasDarkBackground(t *testing.T) {
+	// a black background, but a light color scheme
+	o, tty := fakeOutput("\x1b]10;rgb:0000/0000/0000\x1b\\\x1b[?62;22c"+
+		"\x1b]11;rgb:0000/0000/0000\x1b\\\x1b[?62;22c"+
+		"\x1b[?997;2n\x1b[?62;22c", WithColorCache(true))
+	tty.out.Reset()
+
+	if o.HasDarkBackground() {
+		t.Errorf("Expected light background")
+	}
+	if tty.out.Len() != 0 {
+		t.Errorf("Expected cached values, got query %q", tty.out.String())
+	}
+
+	// without caching, the color scheme doesn't get queried
+	o, tty = fakeOutput("\x1b]11;rgb:0000/0000/0000\x1b\\\x1b[?62;22c")
+	if !o.HasDarkBackground() {
+		t.Errorf("Expected dark background")
+	}
+	if exp := "\x1b]11;?\x1b\\\x1b[c"; tty.out.String() != exp {
+		t.Errorf("Expected query %q, got %q", exp, tty.out.String())
+	}
+}
+
+func TestNotifyColorScheme(t *testing.T) {
+	o, tty := fakeOutput("\x1b[?997;1nfoo\x1b[A\x1b[?997;2n")
+
+	ch, err := o.NotifyColorScheme(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schemes []ColorScheme
+	for c := range ch {
+		schemes = append(schemes, c)
+	}
+
+	if len(schemes) != 2 || schemes[0] != DarkColorScheme || schemes[1] != LightColorScheme {
+		t.Errorf("Expected [dark light], got %v", schemes)
+	}
+
+	exp := "\x1b[?2031h\x1b[?2031l"
+	if tty.out.String() != exp {
+		t.Errorf("Expected %q, got %q", exp, tty.out.String())
+	}
+}
@@ -4,6 +4,7 @@
 package termenv
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strconv"
@@ -54,6 +55,17 @@ func (o Output) backgroundColor() Color {
 	return ANSIColor(0)
 }
 
+func (o Output) disableEcho(_ File) (func(), error) {
+	if o.unsafe {
+		return func() {}, nil
+	}
+	return nil, ErrStatusReport
+}
+
+func (o *Output) waitForData(_ context.Context, _ File) error {
+	return ErrStatusReport
+}
+
 // EnableWindowsANSIConsole enables virtual terminal processing on Windows
 // platforms. This allows the use of ANSI escape sequences in Windows console
 // applications. Ensure this gets called before anything gets rendered with
@@ -0,0 +1,142 @@
+package termenv
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TerminalInfo describes the terminal, as reported by the terminal itself.
+type TerminalInfo struct {
+	// Name is the name of the terminal, e.g. "kitty", "WezTerm" or "xterm".
+	// It is empty if the terminal could not be identified.
+	Name string
+	// Version is the version of the terminal, if known.
+	Version string
+
+	// Class is the conformance level reported by Primary Device Attributes
+	// (DA1), e.g. 62 for VT220 or 64 for VT420.
+	Class int
+	// Features lists the extensions reported by DA1, e.g. 4 for Sixel
+	// graphics or 22 for ANSI color.
+	Features []int
+
+	// Type is the terminal type reported by Secondary Device Attributes
+	// (DA2), e.g. 41 for xterm.
+	Type int
+	// Firmware is the firmware version reported by DA2.
+	Firmware int
+}
+
+// HasFeature reports whether the terminal announced the given DA1 extension.
+func (t TerminalInfo) HasFeature(feature int) bool {
+	for _, f := range t.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// terminalTypes maps DA2 terminal types to terminal names.
+var terminalTypes = map[int]string{
+	41: "xterm",  //nolint:mnd
+	65: "VTE",    //nolint:mnd
+	83: "screen", //nolint:mnd
+	84: "tmux",   //nolint:mnd
+}
+
+// TerminalInfo identifies the terminal by querying its Primary and Secondary
+// Device Attributes (DA1, DA2) and its name and version (XTVERSION). Unlike
+// ColorProfile, this does not rely on environment variables, so it also works
+// over SSH or inside containers.
+func (o *Output) TerminalInfo() (TerminalInfo, error) {
+	replies, err := o.Query(context.Background(), CSI+">q"+CSI+">c", func(r Reply) bool {
+		switch r.Type {
+		case CSIReply:
+			return strings.HasSuffix(r.Payload, "c") &&
+				(strings.HasPrefix(r.Payload, "?") || strings.HasPrefix(r.Payload, ">"))
+		case DCSReply:
+			return strings.HasPrefix(r.Payload, ">|")
+		}
+		return false
+	})
+	if err != nil {
+		return TerminalInfo{}, err
+	}
+
+	return parseTerminalInfo(replies), nil
+}
+
+// parseTerminalInfo parses the replies to DA1, DA2 and XTVERSION queries:
+//   - DA1: "\x1b[?64;1;4;22c"
+//   - DA2: "\x1b[>41;390;0c"
+//   - XTVERSION: "\x1bP>|xterm(390)\x1b\\"
+func parseTerminalInfo(replies []Reply) TerminalInfo {
+	var info TerminalInfo
+	for _, r := range replies {
+		switch r.Type {
+		case CSIReply:
+			if len(r.Payload) < 2 || !strings.HasSuffix(r.Payload, "c") { //nolint:mnd
+				continue
+			}
+			p := parseParams(r.Payload[1 : len(r.Payload)-1])
+			if strings.HasPrefix(r.Payload, "?") {
+				if len(p) > 0 {
+					info.Class = p[0]
+					info.Features = p[1:]
+				}
+				continue
+			}
+			if strings.HasPrefix(r.Payload, ">") && len(p) > 1 {
+				info.Type = p[0]
+				info.Firmware = p[1]
+			}
+
+		case DCSReply:
+			info.Name, info.Version = parseXTVersion(strings.TrimPrefix(r.Payload, ">|"))
+		}
+	}
+
+	// fall back to what DA2 tells us
+	if info.Name == "" {
+		info.Name = terminalTypes[info.Type]
+		switch info.Name {
+		case "xterm":
+			info.Version = strconv.Itoa(info.Firmware)
+		case "VTE":
+			// VTE reports e.g. 0.68.2 as 6802
+			info.Version = fmt.Sprintf("0.%d.%d", info.Firmware/100, info.Firmware%100) //nolint:mnd
+		}
+	}
+
+	return info
+}
+
+// parseXTVersion parses the name and version reported by XTVERSION, which is
+// either "name(version)" or "name version".
+func parseXTVersion(s string) (name, version string) {
+	if i := strings.Index(s, "("); i > 0 && strings.HasSuffix(s, ")") {
+		return s[:i], s[i+1 : len(s)-1]
+	}
+	if i := strings.Index(s, " "); i > 0 {
+		return s[:i], strings.TrimSpace(s[i+1:])
+	}
+	return s, ""
+}
+
+// parseParams parses the semicolon separated numeric parameters of a
+// control sequence. Empty or invalid parameters are treated as 0.
+func parseParams(s string) []int {
+	if s == "" {
+		return nil
+	}
+
+	v := strings.Split(s, ";")
+	p := make([]int, len(v))
+	for i, s := range v {
+		p[i], _ = strconv.Atoi(s)
+	}
+	return p
+}
@@ -0,0 +1,99 @@
+package termenv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTerminalInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		replies []Reply
+		info    TerminalInfo
+	}{
+		{
+			"xterm",
+			[]Reply{
+				{DCSReply, ">|XTerm(390)"},
+				{CSIReply, ">41;390;0c"},
+				{CSIReply, "?64;1;2;6;9;15;16;17;18;21;22;28c"},
+			},
+			TerminalInfo{
+				Name:     "XTerm",
+				Version:  "390",
+				Class:    64,
+				Features: []int{1, 2, 6, 9, 15, 16, 17, 18, 21, 22, 28},
+				Type:     41,
+				Firmware: 390,
+			},
+		},
+		{
+			"wezterm",
+			[]Reply{
+				{DCSReply, ">|WezTerm 20240203-110809-5046fc22"},
+				{CSIReply, ">1;277;0c"},
+				{CSIReply, "?65;4;6;18;22c"},
+			},
+			TerminalInfo{
+				Name:     "WezTerm",
+				Version:  "20240203-110809-5046fc22",
+				Class:    65,
+				Features: []int{4, 6, 18, 22},
+				Type:     1,
+				Firmware: 277,
+			},
+		},
+		{
+			"vte without xtversion",
+			[]Reply{
+				{CSIReply, ">65;6802;1c"},
+				{CSIReply, "?65;1;9c"},
+			},
+			TerminalInfo{
+				Name:     "VTE",
+				Version:  "0.68.2",
+				Class:    65,
+				Features: []int{1, 9},
+				Type:     65,
+				Firmware: 6802,
+			},
+		},
+		{
+			"xterm without xtversion",
+			[]Reply{
+				{CSIReply, ">41;353;0c"},
+				{CSIReply, "?62c"},
+			},
+			TerminalInfo{
+				Name:     "xterm",
+				Version:  "353",
+				Class:    62,
+				Features: []int{},
+				Type:     41,
+				Firmware: 353,
+			},
+		},
+		{
+			"unknown",
+			[]Reply{
+				{CSIReply, "?1;2c"},
+			},
+			TerminalInfo{
+				Class:    1,
+				Features: []int{2},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			info := parseTerminalInfo(test.replies)
+			if !reflect.DeepEqual(info, test.info) {
+				t.Errorf("Expected %+v, got %+v", test.info, info)
+			}
+			if len(test.info.Features) > 0 && !info.HasFeature(test.info.Features[0]) {
+				t.Errorf("Expected feature %d to be supported", test.info.Features[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,338 @@
+package termenv
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrTerminfoNotFound gets returned when no terminfo entry exists for a
+// terminal.
+var ErrTerminfoNotFound = errors.New("terminfo entry not found")
+
+// ErrInvalidTerminfo gets returned when a terminfo entry can't be parsed.
+var ErrInvalidTerminfo = errors.New("invalid terminfo entry")
+
+// Magic numbers of compiled terminfo entries.
+const (
+	terminfoMagic         = 0o432  // 16-bit numbers
+	terminfoExtendedMagic = 0o1036 // 32-bit numbers
+)
+
+// Default locations of the terminfo database.
+var terminfoDirs = []string{
+	"/etc/terminfo",
+	"/lib/terminfo",
+	"/usr/share/terminfo",
+	"/usr/lib/terminfo",
+	"/usr/local/share/terminfo",
+}
+
+// Terminfo is a compiled terminfo entry, describing the capabilities of a
+// terminal. Extended (user-defined) capabilities, like RGB or Tc, are
+// included. Capabilities that are absent or cancelled in the entry are
+// omitted.
+type Terminfo struct {
+	// Names are the terminal's names, e.g. "xterm-256color", followed by its
+	// description.
+	Names   []string
+	Bools   map[string]bool
+	Numbers map[string]int
+	Strings map[string]string
+}
+
+// LoadTerminfo finds and parses the terminfo entry for the given terminal.
+// It searches TERMINFO, ~/.terminfo, TERMINFO_DIRS, and the default system
+// locations, in this order.
+func LoadTerminfo(term string) (*Terminfo, error) {
+	return loadTerminfo(term, &osEnviron{})
+}
+
+// Terminfo returns the terminfo entry for the Output's TERM.
+func (o *Output) Terminfo() (*Terminfo, error) {
+	return loadTerminfo(o.environ.Getenv("TERM"), o.environ)
+}
+
+func loadTerminfo(term string, environ Environ) (*Terminfo, error) {
+	if term == "" || strings.ContainsAny(term, "/\\") || strings.HasPrefix(term, ".") {
+		return nil, ErrTerminfoNotFound
+	}
+
+	for _, dir := range terminfoSearchPath(environ) {
+		// entries are stored in a subdirectory named after their first
+		// character, or its hex value on case-insensitive file systems
+		for _, sub := range []string{term[:1], fmt.Sprintf("%x", term[0])} {
+			b, err := os.ReadFile(filepath.Join(dir, sub, term))
+			if err != nil {
+				continue
+			}
+			return ParseTerminfo(b)
+		}
+	}
+
+	return nil, ErrTerminfoNotFound
+}
+
+func terminfoSearchPath(environ Environ) []string {
+	var dirs []string
+	if dir := environ.Getenv("TERMINFO"); dir != "" {
+		dirs = append(dirs, dir)
+	}
+	if home := environ.Getenv("HOME"); home != "" {
+		dirs = append(dirs, filepath.Join(home, ".terminfo"))
+	}
+	if v := environ.Getenv("TERMINFO_DIRS"); v != "" {
+		for _, dir := range strings.Split(v, ":") {
+			// an empty entry stands for the default locations
+			if dir == "" {
+				dirs = append(dirs, terminfoDirs...)
+				continue
+			}
+			dirs = append(dirs, dir)
+		}
+	}
+
+	return append(dirs, terminfoDirs...)
+}
+
+// ColorProfile returns the color profile advertised by the entry's colors,
+// RGB and Tc capabilities.
+func (t *Terminfo) ColorProfile() Profile {
+	_, rgbBool := t.Bools["RGB"]
+	_, rgbNum := t.Numbers["RGB"]
+	_, rgbStr := t.Strings["RGB"]
+	if rgbBool || rgbNum || rgbStr || t.Bools["Tc"] {
+		return TrueColor
+	}
+
+	colors := t.Numbers["colors"]
+	switch {
+	case colors >= 1<<24:
+		return TrueColor
+	case colors >= 256: //nolint:mnd
+		return ANSI256
+	case colors >= 8: //nolint:mnd
+		return ANSI
+	}
+	return Ascii
+}
+
+// ParseTerminfo parses a compiled terminfo entry, in either the legacy or the
+// extended number format. See term(5).
+func ParseTerminfo(b []byte) (*Terminfo, error) {
+	r := &terminfoReader{b: b}
+
+	// header: magic, size of names, count of booleans, numbers, strings, and
+	// size of the string table
+	h := r.shorts(6) //nolint:mnd
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	numSize := 2
+	switch h[0] {
+	case terminfoMagic:
+	case terminfoExtendedMagic:
+		numSize = 4
+	default:
+		return nil, ErrInvalidTerminfo
+	}
+	for _, v := range h[1:] {
+		if v < 0 {
+			return nil, ErrInvalidTerminfo
+		}
+	}
+	if h[2] > len(terminfoBoolNames) || h[3] > len(terminfoNumberNames) || h[4] > len(terminfoStringNames) {
+		return nil, ErrInvalidTerminfo
+	}
+
+	ti := &Terminfo{
+		Bools:   map[string]bool{},
+		Numbers: map[string]int{},
+		Strings: map[string]string{},
+	}
+
+	names := r.bytes(h[1])
+	ti.Names = strings.Split(strings.TrimRight(string(names), "\x00"), "|")
+
+	bools := r.bytes(h[2])
+	r.align()
+	nums := r.numbers(h[3], numSize)
+	strs := r.shorts(h[4])
+	table := r.bytes(h[5])
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	for i, v := range bools {
+		if v == 1 {
+			ti.Bools[terminfoBoolNames[i]] = true
+		}
+	}
+	for i, v := range nums {
+		if v >= 0 {
+			ti.Numbers[terminfoNumberNames[i]] = v
+		}
+	}
+	for i, off := range strs {
+		if s, ok := terminfoString(table, off); ok {
+			ti.Strings[terminfoStringNames[i]] = s
+		}
+	}
+
+	// the extended section is optional
+	r.align()
+	if r.pos >= len(b) {
+		return ti, nil
+	}
+	if err := r.extended(ti, numSize); err != nil {
+		return nil, err
+	}
+
+	return ti, nil
+}
+
+// extended parses the section containing the extended capabilities.
+func (r *terminfoReader) extended(ti *Terminfo, numSize int) error {
+	// header: count of booleans, numbers, strings, used entries of the string
+	// table, and its size
+	h := r.shorts(5) //nolint:mnd
+	if r.err != nil {
+		return r.err
+	}
+	for _, v := range h {
+		if v < 0 {
+			return ErrInvalidTerminfo
+		}
+	}
+
+	bools := r.bytes(h[0])
+	r.align()
+	nums := r.numbers(h[1], numSize)
+	// offsets of the string values, followed by the offsets of the names
+	offs := r.shorts(h[2] + h[0] + h[1] + h[2])
+	table := r.bytes(h[4])
+	if r.err != nil {
+		return r.err
+	}
+	strs, nameOffs := offs[:h[2]], offs[h[2]:]
+
+	// the string table holds the values, followed by the names of all
+	// extended capabilities; name offsets are relative to the first name
+	var namesStart int
+	for _, off := range strs {
+		if s, ok := terminfoString(table, off); ok && off+len(s)+1 > namesStart {
+			namesStart = off + len(s) + 1
+		}
+	}
+	if namesStart > len(table) {
+		return ErrInvalidTerminfo
+	}
+	name := func(i int) (string, error) {
+		s, ok := terminfoString(table[namesStart:], nameOffs[i])
+		if !ok {
+			return "", ErrInvalidTerminfo
+		}
+		return s, nil
+	}
+
+	for i, v := range bools {
+		n, err := name(i)
+		if err != nil {
+			return err
+		}
+		if v == 1 {
+			ti.Bools[n] = true
+		}
+	}
+	for i, v := range nums {
+		n, err := name(h[0] + i)
+		if err != nil {
+			return err
+		}
+		if v >= 0 {
+			ti.Numbers[n] = v
+		}
+	}
+	for i, off := range strs {
+		n, err := name(h[0] + h[1] + i)
+		if err != nil {
+			return err
+		}
+		if s, ok := terminfoString(table, off); ok {
+			ti.Strings[n] = s
+		}
+	}
+
+	return nil
+}
+
+// terminfoString returns the NUL-terminated string at offset off of the
+// string table. Negative offsets mark absent or cancelled capabilities.
+func terminfoString(table []byte, off int) (string, bool) {
+	if off < 0 || off >= len(table) {
+		return "", false
+	}
+
+	s := table[off:]
+	i := strings.IndexByte(string(s), 0)
+	if i < 0 {
+		return "", false
+	}
+	return string(s[:i]), true
+}
+
+// terminfoReader reads the little-endian values of a compiled terminfo entry.
+// After the first error, all reads return zero values.
+type terminfoReader struct {
+	b   []byte
+	pos int
+	err error
+}
+
+func (r *terminfoReader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || r.pos+n > len(r.b) {
+		r.err = ErrInvalidTerminfo
+		return nil
+	}
+
+	b := r.b[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+// shorts reads n signed 16-bit values.
+func (r *terminfoReader) shorts(n int) []int {
+	return r.numbers(n, 2) //nolint:mnd
+}
+
+// numbers reads n signed values of the given size (2 or 4 bytes).
+func (r *terminfoReader) numbers(n, size int) []int {
+	b := r.bytes(n * size)
+	if r.err != nil {
+		return nil
+	}
+
+	v := make([]int, n)
+	for i := range v {
+		if size == 4 { //nolint:mnd
+			v[i] = int(int32(binary.LittleEndian.Uint32(b[i*size:]))) //nolint:gosec
+		} else {
+			v[i] = int(int16(binary.LittleEndian.Uint16(b[i*size:]))) //nolint:gosec
+		}
+	}
+	return v
+}
+
+// align skips to the next even offset.
+func (r *terminfoReader) align() {
+	if r.pos%2 == 1 && r.pos < len(r.b) {
+		r.pos++
+	}
+}
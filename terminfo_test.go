@@ -0,0 +1,135 @@
+package termenv
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+type mapEnv map[string]string
+
+func (e mapEnv) Environ() []string {
+	var env []string
+	for k, v := range e {
+		env = append(env, k+"="+v)
+	}
+	return env
+}
+
+func (e mapEnv) Getenv(key string) string {
+	return e[key]
+}
+
+func TestParseTerminfo(t *testing.T) {
+	tests := []struct {
+		file    string
+		name    string
+		colors  int
+		setaf   string
+		profile Profile
+	}{
+		// legacy format
+		{"x/xterm", "xterm", 8, "\x1b[3%p1%dm", ANSI},
+		// extended number format
+		{"x/xterm-256color", "xterm-256color", 256, "\x1b[%?%p1%{8}%<%t3%p1%d%e%p1%{16}%<%t9%p1%{8}%-%d%e38;5;%p1%d%;m", ANSI256},
+		{"x/xterm-direct", "xterm-direct", 1 << 24, "\x1b[%?%p1%{8}%<%t3%p1%d%e38:2::%p1%{65536}%/%d:%p1%{256}%/%{255}%&%d:%p1%{255}%&%d%;m", TrueColor},
+		// legacy format with extended capabilities
+		{"74/termenv-tc", "termenv-tc", 256, "\x1b[38;5;%p1%dm", TrueColor},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b, err := os.ReadFile("./testdata/terminfo/" + test.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ti, err := ParseTerminfo(b)
+			if err != nil {
+				t.Fatalf("unexpected error parsing terminfo: %v", err)
+			}
+			if ti.Names[0] != test.name {
+				t.Errorf("Expected name %s, got %s", test.name, ti.Names[0])
+			}
+			if !ti.Bools["am"] {
+				t.Errorf("Expected am capability")
+			}
+			if ti.Numbers["colors"] != test.colors {
+				t.Errorf("Expected %d colors, got %d", test.colors, ti.Numbers["colors"])
+			}
+			if ti.Strings["setaf"] != test.setaf {
+				t.Errorf("Expected setaf %q, got %q", test.setaf, ti.Strings["setaf"])
+			}
+			if p := ti.ColorProfile(); p != test.profile {
+				t.Errorf("Expected profile %s, got %s", test.profile.Name(), p.Name())
+			}
+		})
+	}
+}
+
+func TestParseTerminfoExtended(t *testing.T) {
+	b, err := os.ReadFile("./testdata/terminfo/74/termenv-tc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ti, err := ParseTerminfo(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ti.Bools["Tc"] {
+		t.Errorf("Expected Tc capability")
+	}
+	if exp := "\x1b[4:%p1%dm"; ti.Strings["Smulx"] != exp {
+		t.Errorf("Expected Smulx %q, got %q", exp, ti.Strings["Smulx"])
+	}
+	if _, ok := ti.Strings["blink"]; ok {
+		t.Errorf("Expected blink capability to be absent")
+	}
+}
+
+func TestParseTerminfoInvalid(t *testing.T) {
+	b, err := os.ReadFile("./testdata/terminfo/x/xterm-256color")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range [][]byte{
+		nil,
+		[]byte("foobar"),
+		b[:12],
+		b[:len(b)/2],
+	} {
+		if _, err := ParseTerminfo(v); !errors.Is(err, ErrInvalidTerminfo) {
+			t.Errorf("Expected %v, got %v", ErrInvalidTerminfo, err)
+		}
+	}
+}
+
+func TestLoadTerminfo(t *testing.T) {
+	tests := []struct {
+		name string
+		env  mapEnv
+		err  error
+	}{
+		{"TERMINFO", mapEnv{"TERM": "xterm-direct", "TERMINFO": "./testdata/terminfo"}, nil},
+		{"TERMINFO_DIRS", mapEnv{"TERM": "xterm-direct", "TERMINFO_DIRS": "/nonexistent:./testdata/terminfo"}, nil},
+		{"hex directory", mapEnv{"TERM": "termenv-tc", "TERMINFO": "./testdata/terminfo"}, nil},
+		{"not found", mapEnv{"TERM": "termenv-nonexistent", "TERMINFO": "./testdata/terminfo"}, ErrTerminfoNotFound},
+		{"path", mapEnv{"TERM": "../x/xterm", "TERMINFO": "./testdata/terminfo/x"}, ErrTerminfoNotFound},
+		{"no TERM", mapEnv{"TERMINFO": "./testdata/terminfo"}, ErrTerminfoNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			o := NewOutput(os.Stdout, WithEnvironment(test.env), WithProfile(TrueColor))
+			ti, err := o.Terminfo()
+			if !errors.Is(err, test.err) {
+				t.Fatalf("Expected %v, got %v", test.err, err)
+			}
+			if err == nil && ti.Names[0] != test.env["TERM"] {
+				t.Errorf("Expected %s, got %s", test.env["TERM"], ti.Names[0])
+			}
+		})
+	}
+}
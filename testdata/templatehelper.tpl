@@ -11,4 +11,9 @@ Plain
 {{ Overline "Overline" }}
 {{ Blink "Blink" }}
 {{ Reverse "Reverse" }}
-{{ CrossOut "CrossOut" }}
\ No newline at end of file
+{{ CrossOut "CrossOut" }}
+{{ DoubleUnderline "DoubleUnderline" }}
+{{ RapidBlink "RapidBlink" }}
+{{ Conceal "Conceal" }}
+{{ Superscript "Superscript" }}
+{{ Subscript "Subscript" }}
\ No newline at end of file